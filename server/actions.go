@@ -0,0 +1,251 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+)
+
+// Dialog element names of the reschedule dialog
+const (
+	dialogFieldStartAt  = "start_at"
+	dialogFieldDuration = "duration_minutes"
+)
+
+// handlePostAction handles button clicks on meeting posts (/api/actions/<action>)
+func (p *Plugin) handlePostAction(w http.ResponseWriter, r *http.Request, userID, action string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var actionReq model.PostActionIntegrationRequest
+	if err := json.NewDecoder(r.Body).Decode(&actionReq); err != nil {
+		p.API.LogError("[Kontur] Failed to parse post action", "error", err.Error())
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	locale := p.userLocale(userID)
+	meetingID, _ := actionReq.Context["meeting_id"].(string)
+	meeting, err := p.getMeeting(meetingID)
+	if err != nil {
+		p.API.LogWarn("[Kontur] Post action for unknown meeting", "meeting_id", meetingID, "error", err.Error())
+		writePostActionResponse(w, tr(locale, "action.error", tr(locale, "meeting.not_found")))
+		return
+	}
+
+	p.API.LogDebug("[Kontur] Post action received", "action", action, "meeting_id", meeting.ID, RequestFieldUserID, userID)
+
+	// Like the .ics download, the room link is only given to members of the meeting's channel
+	if action == ActionJoin || action == ActionCalendar {
+		if _, appErr := p.API.GetChannelMember(meeting.ChannelID, userID); appErr != nil {
+			writePostActionResponse(w, tr(locale, "action.not_channel_member"))
+			return
+		}
+	}
+
+	switch action {
+	case ActionJoin:
+		writePostActionResponse(w, tr(locale, "post.join_link", meeting.RoomURL))
+	case ActionCalendar:
+		writePostActionResponse(w, tr(locale, "action.calendar", p.meetingICSURL(meeting), googleCalendarURL(meeting, locale)))
+	case ActionCancel:
+		if !p.canManageMeeting(userID, meeting) {
+			writePostActionResponse(w, tr(locale, "action.error", tr(locale, "meeting.cancel_forbidden")))
+			return
+		}
+		if meeting.IsCancelled() {
+			writePostActionResponse(w, tr(locale, "meeting.already_cancelled"))
+			return
+		}
+		if reqErr := p.cancelMeeting(meeting, userID); reqErr != nil {
+			writePostActionResponse(w, tr(locale, "action.error", reqErr.Message))
+			return
+		}
+		writePostActionResponse(w, tr(locale, "action.cancelled"))
+	case ActionReschedule:
+		if !p.canManageMeeting(userID, meeting) {
+			writePostActionResponse(w, tr(locale, "action.error", tr(locale, "meeting.reschedule_forbidden")))
+			return
+		}
+		if meeting.IsCancelled() {
+			writePostActionResponse(w, tr(locale, "action.error", tr(locale, "meeting.cancelled")))
+			return
+		}
+		if err := p.openRescheduleDialog(actionReq.TriggerId, meeting, locale); err != nil {
+			p.API.LogError("[Kontur] Failed to open reschedule dialog", "meeting_id", meeting.ID, "error", err.Error())
+			writePostActionResponse(w, tr(locale, "action.error", tr(locale, "action.dialog_failed")))
+			return
+		}
+		writePostActionResponse(w, "")
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// openRescheduleDialog shows the dialog asking for the new start time and duration
+func (p *Plugin) openRescheduleDialog(triggerID string, meeting *Meeting, locale string) error {
+	loc := loadLocation(meeting.Timezone)
+	dialog := model.OpenDialogRequest{
+		TriggerId: triggerID,
+		URL:       fmt.Sprintf("/plugins/%s/api/dialogs/reschedule", PluginID),
+		Dialog: model.Dialog{
+			CallbackId:  meeting.ID,
+			Title:       tr(locale, "dialog.title"),
+			SubmitLabel: tr(locale, "dialog.submit"),
+			State:       meeting.ID,
+			Elements: []model.DialogElement{
+				{
+					DisplayName: tr(locale, "dialog.start"),
+					Name:        dialogFieldStartAt,
+					Type:        "text",
+					Default:     meeting.StartTime().In(loc).Format("2006-01-02 15:04"),
+					HelpText:    tr(locale, "dialog.start_help", loc.String()),
+				},
+				{
+					DisplayName: tr(locale, "dialog.duration"),
+					Name:        dialogFieldDuration,
+					Type:        "text",
+					SubType:     "number",
+					Default:     strconv.Itoa(meeting.DurationMinutes),
+				},
+			},
+		},
+	}
+
+	if appErr := p.API.OpenInteractiveDialog(dialog); appErr != nil {
+		return fmt.Errorf("%s", appErr.Error())
+	}
+	return nil
+}
+
+// handleRescheduleDialog handles the reschedule dialog submission (/api/dialogs/reschedule)
+func (p *Plugin) handleRescheduleDialog(w http.ResponseWriter, r *http.Request, userID string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var submitReq model.SubmitDialogRequest
+	if err := json.NewDecoder(r.Body).Decode(&submitReq); err != nil {
+		p.API.LogError("[Kontur] Failed to parse dialog submission", "error", err.Error())
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+	if submitReq.Cancelled {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	locale := p.userLocale(userID)
+	meeting, err := p.getMeeting(submitReq.State)
+	if err != nil {
+		writeDialogResponse(w, &model.SubmitDialogResponse{Error: tr(locale, "meeting.not_found")})
+		return
+	}
+	if !p.canManageMeeting(userID, meeting) {
+		writeDialogResponse(w, &model.SubmitDialogResponse{Error: tr(locale, "meeting.reschedule_forbidden")})
+		return
+	}
+
+	startAtLocal, _ := submitReq.Submission[dialogFieldStartAt].(string)
+	durationValue := fmt.Sprintf("%v", submitReq.Submission[dialogFieldDuration])
+	duration, err := strconv.Atoi(strings.TrimSpace(durationValue))
+	if err != nil {
+		writeDialogResponse(w, &model.SubmitDialogResponse{Errors: map[string]string{
+			dialogFieldDuration: tr(locale, "dialog.invalid_duration"),
+		}})
+		return
+	}
+
+	update := &MeetingUpdateRequest{
+		StartAtLocal:    startAtLocal,
+		DurationMinutes: &duration,
+	}
+	req := update.scheduleRequest(meeting)
+	req.locale = locale
+	if errors := validateScheduleFields(req); len(errors) > 0 {
+		writeDialogResponse(w, &model.SubmitDialogResponse{Errors: map[string]string{
+			dialogFieldDuration: joinFieldErrors(errors),
+		}})
+		return
+	}
+
+	if _, reqErr := p.updateMeetingDetails(meeting, update, userID); reqErr != nil {
+		if reqErr.Field == RequestFieldStartAtLocal {
+			writeDialogResponse(w, &model.SubmitDialogResponse{Errors: map[string]string{
+				dialogFieldStartAt: reqErr.Message,
+			}})
+			return
+		}
+		writeDialogResponse(w, &model.SubmitDialogResponse{Error: reqErr.Message})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// canManageMeeting reports whether the user may cancel or change the meeting:
+// the organizer, a channel admin or a system admin
+func (p *Plugin) canManageMeeting(userID string, meeting *Meeting) bool {
+	if userID == meeting.OrganizerID {
+		return true
+	}
+	if member, appErr := p.API.GetChannelMember(meeting.ChannelID, userID); appErr == nil && member.SchemeAdmin {
+		return true
+	}
+	return p.API.HasPermissionTo(userID, model.PermissionManageSystem)
+}
+
+// cancelMeeting cancels the meeting at the provider, marks it cancelled and updates its announcement
+func (p *Plugin) cancelMeeting(meeting *Meeting, userID string) *RequestError {
+	locale := p.userLocale(userID)
+	provider, reqErr := p.getMeetingProvider(locale)
+	if reqErr != nil {
+		return reqErr
+	}
+	if err := provider.CancelMeeting(&ProviderRequest{Meeting: meeting, UserID: userID, Locale: locale}); err != nil {
+		return providerRequestError(err, locale, "provider.cancel_failed")
+	}
+
+	// Only the status changes, so concurrent updates such as provider events aren't lost
+	cancelled, err := p.modifyMeeting(meeting.ID, func(m *Meeting) bool {
+		if m.IsCancelled() {
+			return false
+		}
+		m.Status = MeetingStatusCancelled
+		m.Sequence++
+		return true
+	})
+	if err != nil {
+		p.API.LogError("[Kontur] Failed to save cancelled meeting", "meeting_id", meeting.ID, "error", err.Error())
+		return &RequestError{StatusCode: http.StatusInternalServerError, Field: RequestFieldGeneral,
+			Message: tr(locale, "meeting.cancel_save_failed")}
+	}
+	*meeting = *cancelled
+
+	if err := p.updateMeetingPost(meeting); err != nil {
+		// The meeting is cancelled anyway, the post is cosmetic
+		p.API.LogWarn("[Kontur] Failed to update cancelled meeting post", "meeting_id", meeting.ID, "error", err.Error())
+	}
+
+	p.API.LogInfo("[Kontur] Meeting cancelled", "meeting_id", meeting.ID, RequestFieldUserID, userID)
+	return nil
+}
+
+// writePostActionResponse answers a post action with an optional ephemeral message
+func writePostActionResponse(w http.ResponseWriter, ephemeralText string) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(&model.PostActionIntegrationResponse{EphemeralText: ephemeralText})
+}
+
+// writeDialogResponse answers a dialog submission with errors
+func writeDialogResponse(w http.ResponseWriter, response *model.SubmitDialogResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
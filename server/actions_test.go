@@ -0,0 +1,65 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostActionRoomLinkRequiresChannelMembership(t *testing.T) {
+	const roomURL = "https://talk.example.com/secret-room"
+	body := `{"context":{"meeting_id":"meeting1"}}`
+
+	for _, action := range []string{ActionJoin, ActionCalendar} {
+		t.Run(action, func(t *testing.T) {
+			p, api := newTestPlugin(t)
+			require.NoError(t, p.updateMeeting(&Meeting{ID: "meeting1", ChannelID: "channel", OrganizerID: "organizer", RoomURL: roomURL}))
+			api.On("GetChannelMember", "channel", "stranger").Return(nil, model.NewAppError("GetChannelMember", "not_found", nil, "", http.StatusNotFound))
+			api.On("GetUser", "stranger").Return(&model.User{Id: "stranger", Locale: "en"}, nil)
+
+			w := serve(p, http.MethodPost, "/api/actions/"+action, "stranger", body)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.NotContains(t, w.Body.String(), roomURL)
+			assert.Contains(t, w.Body.String(), "You are not a member of the meeting's channel")
+		})
+	}
+
+	t.Run("member", func(t *testing.T) {
+		p, api := newTestPlugin(t)
+		require.NoError(t, p.updateMeeting(&Meeting{ID: "meeting1", ChannelID: "channel", OrganizerID: "organizer", RoomURL: roomURL}))
+		api.On("GetChannelMember", "channel", "member").Return(&model.ChannelMember{ChannelId: "channel", UserId: "member"}, nil)
+		api.On("GetUser", "member").Return(&model.User{Id: "member", Locale: "ru"}, nil)
+
+		w := serve(p, http.MethodPost, "/api/actions/"+ActionJoin, "member", body)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), roomURL)
+	})
+}
+
+func TestCancelMeetingKeepsConcurrentUpdates(t *testing.T) {
+	p, api := newTestPlugin(t)
+	p.configuration.MeetingProvider = ProviderJitsi
+	api.On("GetUser", "organizer").Return(&model.User{Id: "organizer", Locale: "ru"}, nil)
+
+	stale := &Meeting{ID: "meeting1", Status: MeetingStatusScheduled, ChannelID: "channel", OrganizerID: "organizer"}
+	require.NoError(t, p.updateMeeting(stale))
+
+	// A provider event marks the call as started after the caller has loaded the meeting
+	live := *stale
+	live.LiveStatus = LiveStatusStarted
+	require.NoError(t, p.updateMeeting(&live))
+
+	require.Nil(t, p.cancelMeeting(stale, "organizer"))
+
+	stored, err := p.getMeeting("meeting1")
+	require.NoError(t, err)
+	assert.Equal(t, MeetingStatusCancelled, stored.Status)
+	assert.Equal(t, LiveStatusStarted, stored.LiveStatus)
+	assert.Equal(t, 1, stored.Sequence)
+	assert.Equal(t, MeetingStatusCancelled, stale.Status)
+}
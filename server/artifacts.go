@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+)
+
+// postRecordingLink replies in the meeting thread with the recording link, in the locale of the announcement
+func (p *Plugin) postRecordingLink(meeting *Meeting) error {
+	message := tr(meeting.Locale, "artifacts.recording", localizedMeetingTitle(meeting, meeting.Locale), meeting.RecordingURL)
+	return p.replyInMeetingThread(meeting, message, nil)
+}
+
+// postTranscript replies in the meeting thread with the transcript link and, if enabled, the transcript as a file
+func (p *Plugin) postTranscript(meeting *Meeting, transcriptText string) error {
+	message := tr(meeting.Locale, "artifacts.transcript", localizedMeetingTitle(meeting, meeting.Locale))
+	if meeting.TranscriptURL != "" {
+		message += tr(meeting.Locale, "artifacts.transcript_link", meeting.TranscriptURL)
+	}
+
+	var fileIDs []string
+	if transcriptText != "" && p.getConfiguration().AttachTranscript {
+		filename := fmt.Sprintf("transcript-%s.txt", meeting.StartTime().In(loadLocation(meeting.Timezone)).Format("2006-01-02-1504"))
+		fileInfo, appErr := p.API.UploadFile([]byte(transcriptText), meeting.ChannelID, filename)
+		if appErr != nil {
+			// The link is still useful without the file
+			p.API.LogWarn("[Kontur] Failed to upload transcript", "meeting_id", meeting.ID, "error", appErr.Error())
+		} else {
+			fileIDs = []string{fileInfo.Id}
+		}
+	}
+	if meeting.TranscriptURL == "" && len(fileIDs) == 0 {
+		return nil
+	}
+
+	return p.replyInMeetingThread(meeting, message, fileIDs)
+}
+
+// replyInMeetingThread posts a bot reply under the meeting announcement
+func (p *Plugin) replyInMeetingThread(meeting *Meeting, message string, fileIDs []string) error {
+	if meeting.PostID == "" {
+		return fmt.Errorf("meeting %s has no announcement post", meeting.ID)
+	}
+
+	post := &model.Post{
+		ChannelId: meeting.ChannelID,
+		UserId:    p.botUserID,
+		RootId:    meeting.threadRootID(),
+		Message:   message,
+		FileIds:   fileIDs,
+	}
+	post.AddProp(PostPropMeetingID, meeting.ID)
+
+	if _, appErr := p.API.CreatePost(post); appErr != nil {
+		return fmt.Errorf("failed to reply in meeting thread: %s", appErr.Error())
+	}
+	return nil
+}
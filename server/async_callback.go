@@ -0,0 +1,253 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+)
+
+// Asynchronous meeting creation settings
+const (
+	CallbackPathMeetingCreated = "/api/callback/meeting-created"
+	AsyncCallbackTimeout       = 10 * time.Minute // Pending meetings fail if the room doesn't arrive in time
+	kvCallbackSecretKey        = "callback_secret"
+	pendingLockKey             = "job_lock_pending_meetings"
+	pendingLockTTLSeconds      = 50
+)
+
+// meetingCallback returns the callback URL and the signed token for a pending meeting
+func (p *Plugin) meetingCallback(meetingID string) (string, string, error) {
+	siteURL := p.siteURL()
+	if siteURL == "" {
+		return "", "", fmt.Errorf("SiteURL is not configured")
+	}
+
+	token, err := p.callbackToken(meetingID)
+	if err != nil {
+		return "", "", err
+	}
+	return fmt.Sprintf("%s/plugins/%s%s", siteURL, PluginID, CallbackPathMeetingCreated), token, nil
+}
+
+// callbackToken signs the meeting ID with the plugin callback secret
+func (p *Plugin) callbackToken(meetingID string) (string, error) {
+	secret, err := p.getCallbackSecret()
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("meeting-created:" + meetingID))
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// verifyCallbackToken checks the token delivered with a callback in constant time
+func (p *Plugin) verifyCallbackToken(meetingID, token string) bool {
+	expected, err := p.callbackToken(meetingID)
+	if err != nil {
+		p.API.LogError("[Kontur] Failed to compute callback token", "error", err.Error())
+		return false
+	}
+	return hmac.Equal([]byte(expected), []byte(token))
+}
+
+// getCallbackSecret returns the cluster-wide callback secret, generating it on first use
+func (p *Plugin) getCallbackSecret() ([]byte, error) {
+	secret, appErr := p.API.KVGet(kvCallbackSecretKey)
+	if appErr != nil {
+		return nil, fmt.Errorf("failed to load callback secret: %s", appErr.Error())
+	}
+	if secret != nil {
+		return secret, nil
+	}
+
+	generated := make([]byte, 32)
+	if _, err := rand.Read(generated); err != nil {
+		return nil, fmt.Errorf("failed to generate callback secret: %w", err)
+	}
+	// Another node may generate the secret at the same time: only the first one is kept
+	if _, appErr := p.API.KVSetWithOptions(kvCallbackSecretKey, generated, model.PluginKVSetOptions{Atomic: true, OldValue: nil}); appErr != nil {
+		return nil, fmt.Errorf("failed to save callback secret: %s", appErr.Error())
+	}
+	secret, appErr = p.API.KVGet(kvCallbackSecretKey)
+	if appErr != nil || secret == nil {
+		return nil, fmt.Errorf("failed to reload callback secret")
+	}
+	return secret, nil
+}
+
+// handleMeetingCreatedCallback handles POST /api/callback/meeting-created from the provider.
+// It is not called by a Mattermost user: the request is authenticated by the token sent with the webhook.
+func (p *Plugin) handleMeetingCreatedCallback(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, RequestFieldGeneral, "Method not allowed. Use POST.")
+		return
+	}
+
+	var callback map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&callback); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, RequestFieldGeneral, "Invalid JSON: "+err.Error())
+		return
+	}
+
+	meetingID, _ := callback["meeting_id"].(string)
+	token, _ := callback["token"].(string)
+	if meetingID == "" || token == "" || !p.verifyCallbackToken(meetingID, token) {
+		p.API.LogWarn("[Kontur] Meeting callback rejected: invalid token", "meeting_id", meetingID)
+		writeErrorResponse(w, http.StatusUnauthorized, RequestFieldGeneral, "Invalid callback token")
+		return
+	}
+
+	meeting, err := p.getMeeting(meetingID)
+	if err != nil {
+		if err == ErrMeetingNotFound {
+			writeErrorResponse(w, http.StatusNotFound, RequestFieldMeetingID, "Meeting not found")
+			return
+		}
+		p.API.LogError("[Kontur] Failed to load meeting", "meeting_id", meetingID, "error", err.Error())
+		writeErrorResponse(w, http.StatusInternalServerError, RequestFieldGeneral, "Failed to load meeting")
+		return
+	}
+
+	if !meeting.IsPending() {
+		// Repeated delivery or a meeting cancelled while its room was being created
+		p.API.LogDebug("[Kontur] Meeting callback ignored", "meeting_id", meetingID, "status", meeting.Status)
+		writeCallbackResponse(w, "ignored")
+		return
+	}
+
+	if status, _ := callback["status"].(string); status == "error" {
+		reason, _ := callback["message"].(string)
+		if reason == "" {
+			reason = tr(meeting.Locale, "provider.room_failed")
+		}
+		if !p.failPendingMeeting(meeting, reason) {
+			writeCallbackResponse(w, "ignored")
+			return
+		}
+		writeCallbackResponse(w, "success")
+		return
+	}
+
+	roomURL := extractRoomURL(callback)
+	if roomURL == "" {
+		writeErrorResponse(w, http.StatusBadRequest, WebhookFieldRoomURL, "room_url is required")
+		return
+	}
+
+	roomID := extractRoomID(callback)
+	delivered := false
+	meeting, err = p.modifyMeeting(meetingID, func(m *Meeting) bool {
+		// The meeting may have expired or been cancelled since it was loaded
+		if !m.IsPending() {
+			return false
+		}
+		m.RoomURL, m.RoomID, m.Status = roomURL, roomID, MeetingStatusScheduled
+		delivered = true
+		return true
+	})
+	if err != nil {
+		p.API.LogError("[Kontur] Failed to save created meeting", "meeting_id", meetingID, "error", err.Error())
+		writeErrorResponse(w, http.StatusInternalServerError, RequestFieldGeneral, "Failed to save meeting")
+		return
+	}
+	if !delivered {
+		p.API.LogDebug("[Kontur] Meeting callback ignored", "meeting_id", meetingID, "status", meeting.Status)
+		writeCallbackResponse(w, "ignored")
+		return
+	}
+	if err := p.removeFromIndex(kvPendingIndexKey, meeting.ID); err != nil {
+		p.API.LogError("[Kontur] Failed to unindex pending meeting", "meeting_id", meeting.ID, "error", err.Error())
+	}
+	if meeting.SeriesID != "" {
+		p.setSeriesRoom(meeting.SeriesID, meeting.RoomURL, meeting.RoomID)
+	}
+	if err := p.updateMeetingPost(meeting); err != nil {
+		p.API.LogWarn("[Kontur] Failed to update meeting post", "meeting_id", meeting.ID, "error", err.Error())
+	}
+
+	p.API.LogInfo("[Kontur] Meeting room delivered by callback", "meeting_id", meeting.ID)
+	writeCallbackResponse(w, "success")
+}
+
+// failPendingMeeting cancels a meeting whose room was never created and shows the reason in its post.
+// Returns false if the meeting is no longer pending, e.g. the room arrived meanwhile.
+func (p *Plugin) failPendingMeeting(meeting *Meeting, reason string) bool {
+	failed := false
+	fresh, err := p.modifyMeeting(meeting.ID, func(m *Meeting) bool {
+		if !m.IsPending() {
+			return false
+		}
+		m.Status = MeetingStatusCancelled
+		m.FailureReason = reason
+		failed = true
+		return true
+	})
+	if err != nil {
+		p.API.LogError("[Kontur] Failed to save failed meeting", "meeting_id", meeting.ID, "error", err.Error())
+		return false
+	}
+	*meeting = *fresh
+	if !failed {
+		return false
+	}
+	if err := p.removeFromIndex(kvPendingIndexKey, meeting.ID); err != nil {
+		p.API.LogError("[Kontur] Failed to unindex pending meeting", "meeting_id", meeting.ID, "error", err.Error())
+	}
+	if err := p.updateMeetingPost(meeting); err != nil {
+		p.API.LogWarn("[Kontur] Failed to update meeting post", "meeting_id", meeting.ID, "error", err.Error())
+	}
+
+	p.API.LogWarn("[Kontur] Meeting creation failed", "meeting_id", meeting.ID, "reason", reason)
+	return true
+}
+
+// expirePendingMeetings fails pending meetings whose callback didn't arrive within AsyncCallbackTimeout
+func (p *Plugin) expirePendingMeetings(now time.Time) {
+	meetings, err := p.getPendingMeetings()
+	if err != nil {
+		p.API.LogError("[Kontur] Failed to load pending meetings", "error", err.Error())
+		return
+	}
+
+	stale := []string{}
+	for _, meeting := range meetings {
+		if !meeting.IsPending() {
+			stale = append(stale, meeting.ID)
+			continue
+		}
+		if now.Sub(model.GetTimeForMillis(meeting.CreateAt)) < AsyncCallbackTimeout {
+			continue
+		}
+
+		if !p.failPendingMeeting(meeting, tr(meeting.Locale, "provider.room_timeout")) {
+			continue
+		}
+
+		// A late room would never be used: let the provider release it
+		if provider, reqErr := p.getMeetingProvider(meeting.Locale); reqErr == nil {
+			if err := provider.CancelMeeting(&ProviderRequest{Meeting: meeting, UserID: meeting.OrganizerID, Locale: meeting.Locale}); err != nil {
+				p.API.LogWarn("[Kontur] Failed to cancel expired meeting at provider", "meeting_id", meeting.ID, "error", err.Error())
+			}
+		}
+	}
+
+	if len(stale) > 0 {
+		if err := p.removeFromIndex(kvPendingIndexKey, stale...); err != nil {
+			p.API.LogError("[Kontur] Failed to prune pending meetings", "error", err.Error())
+		}
+	}
+}
+
+// writeCallbackResponse answers the provider callback
+func writeCallbackResponse(w http.ResponseWriter, status string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"status": status})
+}
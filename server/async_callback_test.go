@@ -0,0 +1,69 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPendingTestPlugin stores a meeting waiting for its room, created at createdAt
+func newPendingTestPlugin(t *testing.T, createdAt time.Time) (*Plugin, *memoryKV, *Meeting) {
+	p, _, kv := newTestPluginWithKV(t)
+	p.configuration.MeetingProvider = ProviderJitsi
+
+	meeting := &Meeting{
+		ID:              "meeting1",
+		OperationType:   OperationScheduledMeeting,
+		Status:          MeetingStatusPending,
+		ChannelID:       "channel",
+		OrganizerID:     "organizer",
+		StartAt:         model.GetMillisForTime(createdAt.Add(time.Hour)),
+		DurationMinutes: 30,
+		Timezone:        DefaultTimezone,
+		CreateAt:        model.GetMillisForTime(createdAt),
+	}
+	require.NoError(t, p.saveMeeting(meeting))
+	return p, kv, meeting
+}
+
+func TestMeetingCreatedCallbackIgnoresMeetingExpiredMeanwhile(t *testing.T) {
+	p, kv, meeting := newPendingTestPlugin(t, time.Now())
+	token, err := p.callbackToken(meeting.ID)
+	require.NoError(t, err)
+
+	expired := *meeting
+	expired.Status = MeetingStatusCancelled
+	expired.FailureReason = "timeout"
+	onFirstMeetingRead(t, p, kv, &expired)
+
+	w := serve(p, http.MethodPost, CallbackPathMeetingCreated, "",
+		`{"meeting_id":"meeting1","token":"`+token+`","room_url":"https://talk.example.com/room"}`)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"ignored"`)
+	stored, err := p.getMeeting(meeting.ID)
+	require.NoError(t, err)
+	assert.Equal(t, MeetingStatusCancelled, stored.Status)
+	assert.Empty(t, stored.RoomURL)
+}
+
+func TestExpirePendingMeetingsKeepsRoomDeliveredMeanwhile(t *testing.T) {
+	p, kv, meeting := newPendingTestPlugin(t, time.Now().Add(-2*AsyncCallbackTimeout))
+
+	delivered := *meeting
+	delivered.Status = MeetingStatusScheduled
+	delivered.RoomURL = "https://talk.example.com/room"
+	onFirstMeetingRead(t, p, kv, &delivered)
+
+	p.expirePendingMeetings(time.Now())
+
+	stored, err := p.getMeeting(meeting.ID)
+	require.NoError(t, err)
+	assert.Equal(t, MeetingStatusScheduled, stored.Status)
+	assert.Equal(t, "https://talk.example.com/room", stored.RoomURL)
+	assert.Empty(t, stored.FailureReason)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+)
+
+// Bot account used for reminders and other plugin messages
+const (
+	BotUsername    = "kontur-talk"
+	BotDisplayName = "Kontur.Talk"
+	BotDescription = "Бот встреч Kontur.Talk: напоминания и уведомления о встречах"
+)
+
+// ensureBotUser returns the plugin bot user ID, creating the bot if it doesn't exist yet
+func (p *Plugin) ensureBotUser() (string, error) {
+	if user, appErr := p.API.GetUserByUsername(BotUsername); appErr == nil && user != nil {
+		if !user.IsBot {
+			return "", fmt.Errorf("username @%s is taken by a regular user", BotUsername)
+		}
+
+		// Reactivate the bot if it was disabled
+		bot, appErr := p.API.GetBot(user.Id, true)
+		if appErr != nil {
+			return "", fmt.Errorf("failed to get bot @%s: %s", BotUsername, appErr.Error())
+		}
+		if bot.DeleteAt != 0 {
+			if _, appErr := p.API.UpdateBotActive(bot.UserId, true); appErr != nil {
+				return "", fmt.Errorf("failed to reactivate bot @%s: %s", BotUsername, appErr.Error())
+			}
+		}
+		return user.Id, nil
+	}
+
+	bot, appErr := p.API.CreateBot(&model.Bot{
+		Username:    BotUsername,
+		DisplayName: BotDisplayName,
+		Description: BotDescription,
+	})
+	if appErr != nil {
+		return "", fmt.Errorf("failed to create bot @%s: %s", BotUsername, appErr.Error())
+	}
+
+	p.API.LogInfo("[Kontur] Bot account created", "bot_user_id", bot.UserId)
+	return bot.UserId, nil
+}
+
+// sendDirectMessage sends a message from the plugin bot to a user
+func (p *Plugin) sendDirectMessage(userID, message string) error {
+	if p.botUserID == "" {
+		return fmt.Errorf("bot user is not initialized")
+	}
+
+	channel, appErr := p.API.GetDirectChannel(p.botUserID, userID)
+	if appErr != nil {
+		return fmt.Errorf("failed to get DM channel with %s: %s", userID, appErr.Error())
+	}
+
+	post := &model.Post{
+		ChannelId: channel.Id,
+		UserId:    p.botUserID,
+		Message:   message,
+	}
+	if _, appErr := p.API.CreatePost(post); appErr != nil {
+		return fmt.Errorf("failed to send DM to %s: %s", userID, appErr.Error())
+	}
+	return nil
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/mattermost/mattermost-server/v6/plugin"
+)
+
+// CommandTrigger is the slash command handled by the plugin
+const CommandTrigger = "meeting"
+
+// getCommand returns the /meeting command definition, registered once for everyone and so in the default language
+func getCommand() *model.Command {
+	locale := DefaultLocale
+	autocomplete := model.NewAutocompleteData(CommandTrigger, "[command]", tr(locale, "command.autocomplete.meeting"))
+	autocomplete.AddCommand(model.NewAutocompleteData("now", "", tr(locale, "command.autocomplete.now")))
+
+	schedule := model.NewAutocompleteData("schedule", tr(locale, "command.autocomplete.schedule_hint"), tr(locale, "command.autocomplete.schedule"))
+	autocomplete.AddCommand(schedule)
+
+	autocomplete.AddCommand(model.NewAutocompleteData("list", "", tr(locale, "command.autocomplete.list")))
+	autocomplete.AddCommand(model.NewAutocompleteData("cancel", "<id> [series]", tr(locale, "command.autocomplete.cancel")))
+	autocomplete.AddCommand(model.NewAutocompleteData("help", "", tr(locale, "command.autocomplete.help")))
+
+	return &model.Command{
+		Trigger:          CommandTrigger,
+		DisplayName:      "Kontur.Talk Meeting",
+		Description:      tr(locale, "command.autocomplete.description"),
+		AutoComplete:     true,
+		AutoCompleteDesc: tr(locale, "command.autocomplete.commands", "now, schedule, list, cancel, help"),
+		AutoCompleteHint: "[command]",
+		AutocompleteData: autocomplete,
+	}
+}
+
+// ExecuteCommand handles the /meeting slash command
+func (p *Plugin) ExecuteCommand(c *plugin.Context, args *model.CommandArgs) (*model.CommandResponse, *model.AppError) {
+	// Responses follow the invoking user's Mattermost language
+	locale := p.userLocale(args.UserId)
+
+	fields := strings.Fields(args.Command)
+	if len(fields) == 0 || fields[0] != "/"+CommandTrigger {
+		return ephemeralResponse(tr(locale, "command.unknown", args.Command)), nil
+	}
+
+	subcommand := "help"
+	if len(fields) > 1 {
+		subcommand = fields[1]
+	}
+	params := []string{}
+	if len(fields) > 2 {
+		params = fields[2:]
+	}
+
+	p.API.LogDebug("[Kontur] Slash command received",
+		"subcommand", subcommand,
+		RequestFieldUserID, args.UserId,
+		RequestFieldChannelID, args.ChannelId)
+
+	switch subcommand {
+	case "now":
+		return p.executeCommandNow(args, locale), nil
+	case "schedule":
+		return p.executeCommandSchedule(args, params, locale), nil
+	case "list":
+		return p.executeCommandList(args, locale), nil
+	case "cancel":
+		return p.executeCommandCancel(args, params, locale), nil
+	case "help":
+		return ephemeralResponse(tr(locale, "command.help")), nil
+	default:
+		return ephemeralResponse(tr(locale, "command.unknown_subcommand", subcommand, tr(locale, "command.help"))), nil
+	}
+}
+
+// executeCommandNow creates an instant meeting in the current channel
+func (p *Plugin) executeCommandNow(args *model.CommandArgs, locale string) *model.CommandResponse {
+	req := &ScheduleRequest{
+		ChannelID: args.ChannelId,
+		TeamID:    args.TeamId,
+		UserID:    args.UserId,
+		RootID:    args.RootId,
+		locale:    locale,
+	}
+
+	meeting, reqErr := p.startInstantCall(req)
+	if reqErr != nil {
+		return ephemeralResponse(tr(locale, "command.error", reqErr.Message))
+	}
+
+	if meeting.IsPending() {
+		return ephemeralResponse(tr(locale, "command.now_pending"))
+	}
+	return ephemeralResponse(tr(locale, "command.now_created", meeting.RoomURL))
+}
+
+// executeCommandSchedule parses `<when> <duration> @user...` and schedules a meeting
+func (p *Plugin) executeCommandSchedule(args *model.CommandArgs, params []string, locale string) *model.CommandResponse {
+	// --force books the meeting even if participants are busy
+	force := false
+	if len(params) > 0 && params[len(params)-1] == "--force" {
+		force = true
+		params = params[:len(params)-1]
+	}
+
+	// Trailing @mentions are participants, the token before them is the duration,
+	// everything before the duration describes the start time
+	end := len(params)
+	for end > 0 && strings.HasPrefix(params[end-1], "@") {
+		end--
+	}
+	mentions := params[end:]
+	if end < 2 {
+		return ephemeralResponse(tr(locale, "command.schedule_usage", tr(locale, "command.help")))
+	}
+
+	duration, err := parseCommandDuration(params[end-1], locale)
+	if err != nil {
+		return ephemeralResponse(tr(locale, "command.error", err.Error()))
+	}
+	when := strings.Join(params[:end-1], " ")
+
+	participantIDs := make([]string, 0, len(mentions))
+	for _, mention := range mentions {
+		username := strings.TrimPrefix(mention, "@")
+		user, appErr := p.API.GetUserByUsername(username)
+		if appErr != nil || user == nil {
+			return ephemeralResponse(tr(locale, "command.user_not_found", username))
+		}
+		participantIDs = append(participantIDs, user.Id)
+	}
+
+	req := &ScheduleRequest{
+		ChannelID:       args.ChannelId,
+		TeamID:          args.TeamId,
+		UserID:          args.UserId,
+		RootID:          args.RootId,
+		StartAtLocal:    when,
+		DurationMinutes: duration,
+		ParticipantIDs:  participantIDs,
+		Force:           force,
+		locale:          locale,
+	}
+
+	if errors := validateScheduleFields(req); len(errors) > 0 {
+		return ephemeralResponse(tr(locale, "command.error", joinFieldErrors(errors)))
+	}
+
+	meeting, reqErr := p.scheduleMeeting(req)
+	if reqErr != nil && len(reqErr.Conflicts) > 0 {
+		return ephemeralResponse(tr(locale, "command.force_hint", reqErr.Message))
+	}
+	if reqErr != nil {
+		return ephemeralResponse(tr(locale, "command.error", reqErr.Message))
+	}
+
+	if meeting.IsPending() {
+		return ephemeralResponse(tr(locale, "command.scheduled_pending", meeting.ID))
+	}
+	return ephemeralResponse(tr(locale, "command.scheduled", meeting.ID, meeting.RoomURL))
+}
+
+// executeCommandList shows upcoming meetings of the current channel
+func (p *Plugin) executeCommandList(args *model.CommandArgs, locale string) *model.CommandResponse {
+	// Reading the list needs only channel membership, not the right to post
+	if _, appErr := p.API.GetChannelMember(args.ChannelId, args.UserId); appErr != nil {
+		return ephemeralResponse(tr(locale, "command.error", tr(locale, "access.not_channel_member")))
+	}
+
+	meetings, err := p.getChannelMeetings(args.ChannelId)
+	if err != nil {
+		p.API.LogError("[Kontur] Failed to list meetings", "channel_id", args.ChannelId, "error", err.Error())
+		return ephemeralResponse(tr(locale, "command.list_failed"))
+	}
+
+	now := time.Now()
+	upcoming := make([]*Meeting, 0, len(meetings))
+	for _, meeting := range meetings {
+		if meeting.IsCancelled() || meeting.OperationType == OperationInstantCall || meeting.EndTime().Before(now) {
+			continue
+		}
+		upcoming = append(upcoming, meeting)
+	}
+
+	if len(upcoming) == 0 {
+		return ephemeralResponse(tr(locale, "command.list_empty"))
+	}
+
+	sort.Slice(upcoming, func(i, j int) bool {
+		return upcoming[i].StartAt < upcoming[j].StartAt
+	})
+
+	text := tr(locale, "command.list_header") + "\n\n"
+	for _, meeting := range upcoming {
+		title := meeting.Title
+		if title == "" {
+			title = tr(locale, "command.list_untitled")
+		}
+		link := tr(locale, "command.list_link", meeting.RoomURL)
+		if meeting.IsPending() {
+			link = tr(locale, "command.list_pending")
+		}
+		text += tr(locale, "command.list_item",
+			p.formatMeetingTime(meeting.StartTime(), loadLocation(meeting.Timezone), locale), title, meeting.DurationMinutes, link, meeting.ID) + "\n"
+	}
+
+	return ephemeralResponse(text)
+}
+
+// executeCommandCancel cancels a meeting organized by the user
+func (p *Plugin) executeCommandCancel(args *model.CommandArgs, params []string, locale string) *model.CommandResponse {
+	if len(params) == 0 || len(params) > 2 || (len(params) == 2 && params[1] != "series") {
+		return ephemeralResponse(tr(locale, "command.cancel_usage"))
+	}
+
+	meeting, err := p.getMeeting(params[0])
+	if err != nil {
+		if err == ErrMeetingNotFound {
+			return ephemeralResponse(tr(locale, "command.meeting_not_found", params[0]))
+		}
+		p.API.LogError("[Kontur] Failed to load meeting", "meeting_id", params[0], "error", err.Error())
+		return ephemeralResponse(tr(locale, "command.meeting_load_failed"))
+	}
+
+	if !p.canManageMeeting(args.UserId, meeting) {
+		return ephemeralResponse(tr(locale, "command.cancel_forbidden"))
+	}
+
+	if len(params) == 2 {
+		if meeting.SeriesID == "" {
+			return ephemeralResponse(tr(locale, "command.not_recurring"))
+		}
+		if reqErr := p.cancelSeries(meeting, args.UserId); reqErr != nil {
+			return ephemeralResponse(tr(locale, "command.error", reqErr.Message))
+		}
+		return ephemeralResponse(tr(locale, "command.series_cancelled", meeting.SeriesID))
+	}
+
+	if meeting.IsCancelled() {
+		return ephemeralResponse(tr(locale, "command.already_cancelled"))
+	}
+
+	if reqErr := p.cancelMeeting(meeting, args.UserId); reqErr != nil {
+		return ephemeralResponse(tr(locale, "command.error", reqErr.Message))
+	}
+
+	return ephemeralResponse(tr(locale, "command.cancelled", meeting.ID))
+}
+
+// parseCommandDuration parses duration in minutes ("30") or Go duration format ("1h30m")
+func parseCommandDuration(value, locale string) (int, error) {
+	if minutes, err := strconv.Atoi(value); err == nil {
+		return minutes, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return int(d.Minutes()), nil
+	}
+	return 0, fmt.Errorf("%s", tr(locale, "command.invalid_duration", value))
+}
+
+// joinFieldErrors joins validation error messages into one line
+func joinFieldErrors(errors []map[string]string) string {
+	messages := make([]string, 0, len(errors))
+	for _, e := range errors {
+		messages = append(messages, e["message"])
+	}
+	return strings.Join(messages, "; ")
+}
+
+// ephemeralResponse builds an ephemeral slash command response
+func ephemeralResponse(text string) *model.CommandResponse {
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         text,
+	}
+}
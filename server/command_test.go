@@ -0,0 +1,88 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteCommandUsesUserLocale(t *testing.T) {
+	for _, tc := range []struct {
+		locale, command, want string
+	}{
+		{"ru", "/meeting help", "**Команды /meeting**"},
+		{"en", "/meeting help", "**/meeting commands**"},
+		{"en", "/meeting", "**/meeting commands**"},
+		{"en", "/meeting foo", "Unknown subcommand `foo`"},
+		{"ru", "/meeting cancel", "Укажите id встречи"},
+		{"en", "/meeting schedule tomorrow", "Specify the meeting time and duration"},
+		{"en", "/meeting schedule tomorrow 3pm soon", "invalid duration format: soon"},
+	} {
+		t.Run(tc.locale+" "+tc.command, func(t *testing.T) {
+			p, api := newTestPlugin(t)
+			api.On("GetUser", "user").Return(&model.User{Id: "user", Locale: tc.locale}, nil)
+
+			resp, appErr := p.ExecuteCommand(nil, &model.CommandArgs{Command: tc.command, UserId: "user", ChannelId: "channel"})
+
+			require.Nil(t, appErr)
+			assert.Equal(t, model.CommandResponseTypeEphemeral, resp.ResponseType)
+			assert.Contains(t, resp.Text, tc.want)
+		})
+	}
+}
+
+func TestParseCommandDuration(t *testing.T) {
+	for value, want := range map[string]int{"30": 30, "1h30m": 90, "45m": 45} {
+		got, err := parseCommandDuration(value, LocaleEn)
+		require.NoError(t, err, value)
+		assert.Equal(t, want, got, value)
+	}
+
+	_, err := parseCommandDuration("полчаса", LocaleRu)
+	assert.EqualError(t, err, "неверный формат длительности: полчаса")
+}
+
+func TestExecuteCommandListRequiresOnlyChannelMembership(t *testing.T) {
+	p, api := newTestPlugin(t)
+	api.On("GetUser", "reader").Return(&model.User{Id: "reader", Locale: "en"}, nil)
+	api.On("GetUser", "outsider").Return(&model.User{Id: "outsider", Locale: "en"}, nil)
+	// A read-only member can't post in the channel but still sees its meetings
+	api.On("GetChannelMember", "channel", "reader").Return(&model.ChannelMember{ChannelId: "channel", UserId: "reader"}, nil)
+	api.On("GetChannelMember", "channel", "outsider").Return(nil, model.NewAppError("GetChannelMember", "not_found", nil, "", 404))
+	require.NoError(t, p.saveMeeting(&Meeting{
+		ID:              "meeting1",
+		OperationType:   OperationScheduledMeeting,
+		Status:          MeetingStatusScheduled,
+		Title:           "Standup",
+		ChannelID:       "channel",
+		OrganizerID:     "organizer",
+		StartAt:         model.GetMillisForTime(time.Now().Add(time.Hour)),
+		DurationMinutes: 30,
+		Timezone:        DefaultTimezone,
+		RoomURL:         "https://meet.example.com/room",
+	}))
+
+	resp, appErr := p.ExecuteCommand(nil, &model.CommandArgs{Command: "/meeting list", UserId: "reader", ChannelId: "channel"})
+	require.Nil(t, appErr)
+	assert.Contains(t, resp.Text, "Standup")
+	assert.Contains(t, resp.Text, "meeting1")
+
+	resp, appErr = p.ExecuteCommand(nil, &model.CommandArgs{Command: "/meeting list", UserId: "outsider", ChannelId: "channel"})
+	require.Nil(t, appErr)
+	assert.Equal(t, tr(LocaleEn, "command.error", tr(LocaleEn, "access.not_channel_member")), resp.Text)
+}
+
+func TestGetCommandUsesDefaultLocale(t *testing.T) {
+	command := getCommand()
+
+	assert.Equal(t, tr(DefaultLocale, "command.autocomplete.description"), command.Description)
+	assert.Equal(t, tr(DefaultLocale, "command.autocomplete.meeting"), command.AutocompleteData.HelpText)
+	require.Len(t, command.AutocompleteData.SubCommands, 5)
+	schedule := command.AutocompleteData.SubCommands[1]
+	assert.Equal(t, "schedule", schedule.Trigger)
+	assert.Equal(t, tr(DefaultLocale, "command.autocomplete.schedule_hint"), schedule.Hint)
+	assert.Equal(t, tr(DefaultLocale, "command.autocomplete.schedule"), schedule.HelpText)
+}
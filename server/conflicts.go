@@ -0,0 +1,267 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+)
+
+// Sources of busy intervals
+const (
+	BusySourcePlugin   = "plugin"   // Meetings stored by the plugin
+	BusySourceExternal = "external" // Calendar lookup through the webhook
+)
+
+// OperationFreeBusy asks the webhook for busy intervals of users in a time window
+const OperationFreeBusy = "free_busy"
+
+// conflictMessageLimit caps the conflicts listed in the error message
+const conflictMessageLimit = 5
+
+// BusyInterval is a time range when a user is not available
+type BusyInterval struct {
+	UserID    string
+	MeetingID string // Empty for external intervals
+	ChannelID string // Channel of the meeting, empty for external intervals
+	Title     string
+	Start     time.Time
+	End       time.Time
+	Source    string
+}
+
+// MeetingConflict is an overlap of the requested time with a user's busy interval
+type MeetingConflict struct {
+	UserID       string `json:"user_id"`
+	Username     string `json:"username"`
+	MeetingID    string `json:"meeting_id,omitempty"`
+	Title        string `json:"title"`
+	StartTimeUTC string `json:"start_time_utc"`
+	EndTimeUTC   string `json:"end_time_utc"`
+	Source       string `json:"source"`
+}
+
+// checkConflicts returns a 409 error listing overlaps of [start, end) with the users' other meetings.
+// Titles and IDs of meetings the requester can't see are left out.
+func (p *Plugin) checkConflicts(requesterID string, users []*model.User, start, end time.Time, excludeMeetingID string, loc *time.Location, locale string) *RequestError {
+	busy := p.getBusyIntervals(users, start, end, excludeMeetingID)
+	canSee := p.busyVisibility(requesterID)
+
+	conflicts := []*MeetingConflict{}
+	for _, user := range users {
+		for _, interval := range busy[user.Id] {
+			if !interval.Start.Before(end) || !start.Before(interval.End) {
+				continue
+			}
+			conflict := &MeetingConflict{
+				UserID:       user.Id,
+				Username:     user.Username,
+				StartTimeUTC: interval.Start.UTC().Format(time.RFC3339),
+				EndTimeUTC:   interval.End.UTC().Format(time.RFC3339),
+				Source:       interval.Source,
+			}
+			if canSee(interval) {
+				conflict.MeetingID = interval.MeetingID
+				conflict.Title = interval.Title
+			}
+			conflicts = append(conflicts, conflict)
+		}
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	p.API.LogInfo("[Kontur] Schedule conflicts found", "conflict_count", len(conflicts))
+	return &RequestError{
+		StatusCode: http.StatusConflict,
+		Field:      RequestFieldParticipantIDs,
+		Message:    p.conflictsMessage(conflicts, loc, locale),
+		Conflicts:  conflicts,
+	}
+}
+
+// conflictsMessage describes conflicts for the user, e.g. "@ivan — «Стендап», 20.01.2025, 10:00 (по МСК)"
+func (p *Plugin) conflictsMessage(conflicts []*MeetingConflict, loc *time.Location, locale string) string {
+	lines := make([]string, 0, conflictMessageLimit)
+	for i, conflict := range conflicts {
+		if i == conflictMessageLimit {
+			lines = append(lines, tr(locale, "conflicts.more", len(conflicts)-conflictMessageLimit))
+			break
+		}
+		title := tr(locale, "conflicts.busy")
+		if conflict.Title != "" {
+			title = tr(locale, "conflicts.title", conflict.Title)
+		}
+		start, _ := time.Parse(time.RFC3339, conflict.StartTimeUTC)
+		lines = append(lines, fmt.Sprintf("@%s — %s, %s", conflict.Username, title, p.formatMeetingTime(start, loc, locale)))
+	}
+	return tr(locale, "conflicts.message", strings.Join(lines, "; "))
+}
+
+// busyVisibility returns a check whether the requester may see the title of a busy interval: a plugin meeting
+// in a channel they belong to, or their own calendar event. Channel memberships are looked up once.
+func (p *Plugin) busyVisibility(requesterID string) func(interval BusyInterval) bool {
+	members := map[string]bool{}
+	return func(interval BusyInterval) bool {
+		if interval.Source != BusySourcePlugin {
+			return interval.UserID == requesterID
+		}
+		member, ok := members[interval.ChannelID]
+		if !ok {
+			_, appErr := p.API.GetChannelMember(interval.ChannelID, requesterID)
+			member = appErr == nil
+			members[interval.ChannelID] = member
+		}
+		return member
+	}
+}
+
+// meetingUsers returns the organizer followed by the participants without duplicates
+func meetingUsers(organizer *model.User, participants []*model.User) []*model.User {
+	users := []*model.User{organizer}
+	for _, user := range participants {
+		if user.Id != organizer.Id {
+			users = append(users, user)
+		}
+	}
+	return users
+}
+
+// getBusyIntervals collects busy intervals of the users within [from, to) from stored meetings
+// and, if enabled, from the external calendar lookup
+func (p *Plugin) getBusyIntervals(users []*model.User, from, to time.Time, excludeMeetingID string) map[string][]BusyInterval {
+	busy := make(map[string][]BusyInterval, len(users))
+
+	for _, user := range users {
+		meetings, err := p.getUserMeetings(user.Id)
+		if err != nil {
+			p.API.LogWarn("[Kontur] Failed to load user meetings", RequestFieldUserID, user.Id, "error", err.Error())
+			continue
+		}
+		for _, meeting := range meetings {
+			// Instant calls have no planned end and never block the calendar
+			if meeting.IsCancelled() || meeting.HasEnded() || meeting.DurationMinutes == 0 {
+				continue
+			}
+			if meeting.ID == excludeMeetingID || !meeting.StartTime().Before(to) || !from.Before(meeting.EndTime()) {
+				continue
+			}
+			busy[user.Id] = append(busy[user.Id], BusyInterval{
+				UserID:    user.Id,
+				MeetingID: meeting.ID,
+				ChannelID: meeting.ChannelID,
+				Title:     meeting.Title,
+				Start:     meeting.StartTime(),
+				End:       meeting.EndTime(),
+				Source:    BusySourcePlugin,
+			})
+		}
+	}
+
+	if p.getConfiguration().BusyLookup {
+		external, err := p.lookupExternalBusy(users, from, to)
+		if err != nil {
+			// The lookup is advisory: stored meetings are still checked
+			p.API.LogWarn("[Kontur] External busy lookup failed", "error", err.Error())
+		}
+		for _, interval := range external {
+			busy[interval.UserID] = append(busy[interval.UserID], interval)
+		}
+	}
+
+	return busy
+}
+
+// lookupExternalBusy asks the webhook for busy intervals of the users (operation free_busy).
+// The response is {"busy": [{"user_id" or "email", "start", "end", "title"}]} with RFC3339 times.
+func (p *Plugin) lookupExternalBusy(users []*model.User, from, to time.Time) ([]BusyInterval, error) {
+	webhookURL := p.getConfiguration().WebhookURL
+	if webhookURL == "" {
+		return nil, nil
+	}
+
+	userMaps := make([]map[string]interface{}, 0, len(users))
+	byEmail := make(map[string]string, len(users))
+	byID := make(map[string]bool, len(users))
+	for _, user := range users {
+		userMaps = append(userMaps, map[string]interface{}{
+			"user_id":  user.Id,
+			"username": user.Username,
+			"email":    user.Email,
+		})
+		byEmail[strings.ToLower(user.Email)] = user.Id
+		byID[user.Id] = true
+	}
+
+	serviceName := p.getConfiguration().ServiceName
+	webhookData, err := p.sendWebhook(webhookURL, map[string]interface{}{
+		"operation_type": OperationFreeBusy,
+		"service_name":   serviceName,
+		"time_min":       from.UTC().Format(time.RFC3339),
+		"time_max":       to.UTC().Format(time.RFC3339),
+		"users":          userMaps,
+		"timestamp":      time.Now().Format(time.RFC3339),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Re-decode the generic response into typed entries
+	raw, err := json.Marshal(webhookData["busy"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to read busy intervals: %w", err)
+	}
+	var entries []struct {
+		UserID string `json:"user_id"`
+		Email  string `json:"email"`
+		Start  string `json:"start"`
+		End    string `json:"end"`
+		Title  string `json:"title"`
+	}
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse busy intervals: %w", err)
+	}
+
+	intervals := make([]BusyInterval, 0, len(entries))
+	for _, entry := range entries {
+		userID := entry.UserID
+		if !byID[userID] {
+			userID = byEmail[strings.ToLower(entry.Email)]
+		}
+		start, startErr := time.Parse(time.RFC3339, entry.Start)
+		end, endErr := time.Parse(time.RFC3339, entry.End)
+		if userID == "" || startErr != nil || endErr != nil || !start.Before(end) {
+			p.API.LogDebug("[Kontur] Skipping invalid busy interval", "user_id", entry.UserID, "start", entry.Start, "end", entry.End)
+			continue
+		}
+		intervals = append(intervals, BusyInterval{
+			UserID: userID,
+			Title:  entry.Title,
+			Start:  start,
+			End:    end,
+			Source: BusySourceExternal,
+		})
+	}
+	return intervals, nil
+}
+
+// writeRequestError writes a pipeline error, including the conflicts list when there is one
+func writeRequestError(w http.ResponseWriter, reqErr *RequestError) {
+	if len(reqErr.Conflicts) == 0 {
+		writeErrorResponse(w, reqErr.StatusCode, reqErr.Field, reqErr.Message)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(reqErr.StatusCode)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"errors": []map[string]string{{
+			"field":   reqErr.Field,
+			"message": reqErr.Message,
+		}},
+		"conflicts": reqErr.Conflicts,
+	})
+}
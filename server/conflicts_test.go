@@ -0,0 +1,80 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// storeBusyMeeting stores a meeting of bob in the channel, indexed like a scheduled one
+func storeBusyMeeting(t *testing.T, p *Plugin, id, channelID string, start time.Time) {
+	require.NoError(t, p.saveMeeting(&Meeting{
+		ID:              id,
+		OperationType:   OperationScheduledMeeting,
+		Status:          MeetingStatusScheduled,
+		Title:           "Meeting " + id,
+		ChannelID:       channelID,
+		OrganizerID:     "bob",
+		StartAt:         model.GetMillisForTime(start),
+		DurationMinutes: 60,
+		Timezone:        DefaultTimezone,
+	}))
+}
+
+func TestCheckConflictsRedactsMeetingsOfForeignChannels(t *testing.T) {
+	p, api := newTestPlugin(t)
+	start := time.Now().Add(time.Hour).Truncate(time.Minute)
+	storeBusyMeeting(t, p, "shared", "shared-channel", start)
+	storeBusyMeeting(t, p, "secret", "secret-channel", start.Add(30*time.Minute))
+	api.On("GetChannelMember", "shared-channel", "alice").Return(&model.ChannelMember{ChannelId: "shared-channel", UserId: "alice"}, nil).Once()
+	api.On("GetChannelMember", "secret-channel", "alice").Return(nil, model.NewAppError("GetChannelMember", "not_found", nil, "", http.StatusNotFound)).Once()
+
+	users := []*model.User{{Id: "alice", Username: "alice"}, {Id: "bob", Username: "bob"}}
+	reqErr := p.checkConflicts("alice", users, start, start.Add(2*time.Hour), "", loadLocation(DefaultTimezone), LocaleRu)
+
+	require.NotNil(t, reqErr)
+	require.Equal(t, http.StatusConflict, reqErr.StatusCode)
+	require.Len(t, reqErr.Conflicts, 2)
+	byStart := map[string]*MeetingConflict{}
+	for _, conflict := range reqErr.Conflicts {
+		byStart[conflict.StartTimeUTC] = conflict
+	}
+	shared := byStart[start.UTC().Format(time.RFC3339)]
+	secret := byStart[start.Add(30*time.Minute).UTC().Format(time.RFC3339)]
+	require.NotNil(t, shared)
+	require.NotNil(t, secret)
+	assert.Equal(t, "shared", shared.MeetingID)
+	assert.Equal(t, "Meeting shared", shared.Title)
+	assert.Empty(t, secret.MeetingID)
+	assert.Empty(t, secret.Title)
+	assert.Equal(t, "bob", secret.Username)
+	assert.NotContains(t, reqErr.Message, "Meeting secret")
+}
+
+func TestBusyVisibilityOfExternalIntervals(t *testing.T) {
+	p, _ := newTestPlugin(t)
+	canSee := p.busyVisibility("alice")
+
+	assert.True(t, canSee(BusyInterval{UserID: "alice", Source: BusySourceExternal}))
+	assert.False(t, canSee(BusyInterval{UserID: "bob", Source: BusySourceExternal}))
+}
+
+func TestGetBusyIntervalsSkipsFinishedMeetingsWithoutPruningIndex(t *testing.T) {
+	p, _ := newTestPlugin(t)
+	now := time.Now()
+	storeBusyMeeting(t, p, "past", "channel", now.Add(-3*time.Hour))
+	storeBusyMeeting(t, p, "upcoming", "channel", now.Add(time.Hour))
+
+	busy := p.getBusyIntervals([]*model.User{{Id: "bob"}}, now, now.Add(24*time.Hour), "")
+
+	require.Len(t, busy["bob"], 1)
+	assert.Equal(t, "upcoming", busy["bob"][0].MeetingID)
+	// The user index stays the complete registry of the user's meetings
+	ids, _, err := p.getIndex(kvUserIndexPrefix + "bob")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"past", "upcoming"}, ids)
+}
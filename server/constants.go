@@ -2,10 +2,30 @@ package main
 
 import "time"
 
+// PluginID must match the id in plugin.json
+const PluginID = "com.skyeng.kontur-meeting"
+
 // Webhook response field names
 const (
 	WebhookFieldRoomURL    = "room_url"
 	WebhookFieldMeetingURL = "meeting_url"
+	WebhookFieldRoomID     = "room_id"
+)
+
+// Webhook operation types
+const (
+	OperationScheduledMeeting = "scheduled_meeting"
+	OperationInstantCall      = "instant_call"
+	OperationCancelMeeting    = "cancel_meeting"
+	OperationUpdateMeeting    = "update_meeting"
+)
+
+// Header set by the Mattermost server for authenticated requests to the plugin
+const (
+	HeaderMattermostUserID = "Mattermost-User-ID"
+	HeaderIdempotencyKey   = "Idempotency-Key"
+	HeaderKonturSignature  = "X-Kontur-Signature"
+	HeaderKonturTimestamp  = "X-Kontur-Timestamp"
 )
 
 // Request field names (for error responses)
@@ -15,6 +35,8 @@ const (
 	RequestFieldStartAt        = "start_at"
 	RequestFieldStartAtLocal   = "start_at_local"
 	RequestFieldParticipantIDs = "participant_ids"
+	RequestFieldMeetingID      = "meeting_id"
+	RequestFieldRecurrence     = "recurrence"
 	RequestFieldGeneral        = "general"
 )
 
@@ -24,9 +46,25 @@ const (
 	DateFormatRFC3339 = time.RFC3339
 )
 
+// Default reminder offsets in minutes before the meeting start
+const (
+	DefaultReminderMinutes = "15,1"
+)
+
 // HTTP client timeout
 const (
+	// WebhookTimeout bounds one webhook call including its retries
 	WebhookTimeout = 2 * time.Minute
+	// ProviderStatusTimeout bounds the room status request made while the client waits for GET /api/meetings/{id}
+	ProviderStatusTimeout = 5 * time.Second
+)
+
+// Webhook retry policy
+const (
+	WebhookRetryBaseDelay  = 500 * time.Millisecond
+	WebhookRetryMaxDelay   = 8 * time.Second
+	WebhookMaxRetriesLimit = 5
+	DefaultWebhookRetries  = 2
 )
 
 
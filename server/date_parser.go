@@ -0,0 +1,324 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+)
+
+// Weekday names (Russian and English, including accusative and short forms)
+var naturalWeekdays = map[string]time.Weekday{
+	"понедельник": time.Monday, "пн": time.Monday, "monday": time.Monday, "mon": time.Monday,
+	"вторник": time.Tuesday, "вт": time.Tuesday, "tuesday": time.Tuesday, "tue": time.Tuesday,
+	"среда": time.Wednesday, "среду": time.Wednesday, "ср": time.Wednesday, "wednesday": time.Wednesday, "wed": time.Wednesday,
+	"четверг": time.Thursday, "чт": time.Thursday, "thursday": time.Thursday, "thu": time.Thursday,
+	"пятница": time.Friday, "пятницу": time.Friday, "пт": time.Friday, "friday": time.Friday, "fri": time.Friday,
+	"суббота": time.Saturday, "субботу": time.Saturday, "сб": time.Saturday, "saturday": time.Saturday, "sat": time.Saturday,
+	"воскресенье": time.Sunday, "вс": time.Sunday, "sunday": time.Sunday, "sun": time.Sunday,
+}
+
+// Day offsets relative to today
+var naturalDayOffsets = map[string]int{
+	"сегодня": 0, "today": 0,
+	"завтра": 1, "tomorrow": 1,
+	"послезавтра": 2,
+}
+
+// Units for "через N ..." / "in N ..."
+var naturalUnits = map[string]time.Duration{
+	"минуту": time.Minute, "минуты": time.Minute, "минут": time.Minute, "мин": time.Minute,
+	"minute": time.Minute, "minutes": time.Minute, "min": time.Minute, "mins": time.Minute,
+	"час": time.Hour, "часа": time.Hour, "часов": time.Hour, "ч": time.Hour,
+	"hour": time.Hour, "hours": time.Hour, "h": time.Hour,
+	"день": 24 * time.Hour, "дня": 24 * time.Hour, "дней": 24 * time.Hour,
+	"day": 24 * time.Hour, "days": 24 * time.Hour,
+	"неделю": 7 * 24 * time.Hour, "недели": 7 * 24 * time.Hour, "недель": 7 * 24 * time.Hour,
+	"week": 7 * 24 * time.Hour, "weeks": 7 * 24 * time.Hour,
+}
+
+// Words that only glue the phrase together
+var naturalFillers = map[string]bool{
+	"в": true, "во": true, "на": true, "at": true, "on": true, "this": true,
+	"час": true, "часа": true, "часов": true,
+}
+
+// Prepositions after which "11.30" means a time of day, not a date
+var naturalTimePrepositions = map[string]bool{
+	"в": true, "во": true, "at": true,
+}
+
+// Words that shift a weekday to the following week
+var naturalNextWords = map[string]bool{
+	"next": true, "следующий": true, "следующую": true, "следующее": true, "следующая": true,
+}
+
+// Meridiem words: true for afternoon/evening, false for morning/night
+var naturalMeridiem = map[string]bool{
+	"am": false, "утра": false, "ночи": false,
+	"pm": true, "дня": true, "вечера": true,
+}
+
+var (
+	reClockTime    = regexp.MustCompile(`^(\d{1,2}):(\d{2})$`)
+	reMeridiemTime = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)$`)
+	reBareHour     = regexp.MustCompile(`^\d{1,2}$`)
+	reISODate      = regexp.MustCompile(`^(\d{4})-(\d{2})-(\d{2})$`)
+	reDottedDate   = regexp.MustCompile(`^(\d{1,2})\.(\d{1,2})(?:\.(\d{4}))?$`)
+	reDottedTime   = regexp.MustCompile(`^(\d{1,2})\.(\d{2})$`)
+	reNaturalTrim  = regexp.MustCompile(`[,!?]+`)
+)
+
+// parseNaturalDateTime parses phrases like "завтра в 15:00", "в пятницу 11:30", "через 2 часа",
+// "tomorrow 3pm" or "next monday 10:00" relative to now in the given location
+func parseNaturalDateTime(input string, now time.Time, loc *time.Location) (time.Time, error) {
+	normalized := strings.ToLower(strings.TrimSpace(input))
+	normalized = strings.ReplaceAll(normalized, "ё", "е")
+	normalized = reNaturalTrim.ReplaceAllString(normalized, " ")
+	tokens := strings.Fields(normalized)
+	if len(tokens) == 0 {
+		return time.Time{}, fmt.Errorf("empty date/time")
+	}
+
+	now = now.In(loc)
+
+	// Relative offset: "через 2 часа", "через полчаса", "in 30 minutes", "in an hour"
+	if tokens[0] == "через" || tokens[0] == "in" {
+		offset, err := parseNaturalOffset(tokens[1:])
+		if err != nil {
+			return time.Time{}, err
+		}
+		return now.Add(offset).Truncate(time.Minute), nil
+	}
+
+	var (
+		date     time.Time
+		hasDate  bool
+		weekday  time.Weekday
+		hasDay   bool
+		nextWeek bool
+		hour     = -1
+		minute   int
+	)
+
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+
+		// ISO date and time glued with "T" without offset: 2025-01-20T15:00
+		if parts := strings.SplitN(token, "t", 2); len(parts) == 2 && reISODate.MatchString(parts[0]) {
+			tokens = append(tokens[:i+1], append([]string{parts[1]}, tokens[i+1:]...)...)
+			token = parts[0]
+		}
+
+		switch {
+		case naturalFillers[token]:
+			continue
+		case naturalNextWords[token]:
+			nextWeek = true
+		case token == "полдень" || token == "noon":
+			hour, minute = 12, 0
+		default:
+			if offset, ok := naturalDayOffsets[token]; ok {
+				date = time.Date(now.Year(), now.Month(), now.Day()+offset, 0, 0, 0, 0, loc)
+				hasDate = true
+				continue
+			}
+			if day, ok := naturalWeekdays[token]; ok {
+				weekday = day
+				hasDay = true
+				continue
+			}
+			if m := reISODate.FindStringSubmatch(token); m != nil {
+				year, _ := strconv.Atoi(m[1])
+				month, _ := strconv.Atoi(m[2])
+				day, _ := strconv.Atoi(m[3])
+				var err error
+				if date, err = naturalDate(year, month, day, loc); err != nil {
+					return time.Time{}, err
+				}
+				hasDate = true
+				continue
+			}
+			if m := reClockTime.FindStringSubmatch(token); m != nil {
+				hour, _ = strconv.Atoi(m[1])
+				minute, _ = strconv.Atoi(m[2])
+				// "15:00 дня" style meridiem after clock time
+				if i+1 < len(tokens) {
+					if pm, ok := naturalMeridiem[tokens[i+1]]; ok {
+						hour = applyMeridiem(hour, pm)
+						i++
+					}
+				}
+				continue
+			}
+			// "в 11.30", or "завтра 11.30" once the day is known, is a time written with a dot
+			if m := reDottedTime.FindStringSubmatch(token); m != nil && hour < 0 &&
+				(i > 0 && naturalTimePrepositions[tokens[i-1]] || hasDate || hasDay) {
+				h, _ := strconv.Atoi(m[1])
+				mm, _ := strconv.Atoi(m[2])
+				if h <= 23 && mm <= 59 {
+					hour, minute = h, mm
+					if i+1 < len(tokens) {
+						if pm, ok := naturalMeridiem[tokens[i+1]]; ok {
+							hour = applyMeridiem(hour, pm)
+							i++
+						}
+					}
+					continue
+				}
+			}
+			if m := reDottedDate.FindStringSubmatch(token); m != nil {
+				day, _ := strconv.Atoi(m[1])
+				month, _ := strconv.Atoi(m[2])
+				year := now.Year()
+				if m[3] != "" {
+					year, _ = strconv.Atoi(m[3])
+				}
+				var err error
+				if date, err = naturalDate(year, month, day, loc); err != nil {
+					return time.Time{}, err
+				}
+				if m[3] == "" && date.Before(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)) {
+					// 29.02 without a year may not exist next year
+					if date, err = naturalDate(year+1, month, day, loc); err != nil {
+						return time.Time{}, err
+					}
+				}
+				hasDate = true
+				continue
+			}
+			if m := reMeridiemTime.FindStringSubmatch(token); m != nil {
+				hour, _ = strconv.Atoi(m[1])
+				if m[2] != "" {
+					minute, _ = strconv.Atoi(m[2])
+				}
+				hour = applyMeridiem(hour, m[3] == "pm")
+				continue
+			}
+			if reBareHour.MatchString(token) {
+				hour, _ = strconv.Atoi(token)
+				minute = 0
+				// "3 pm", "в 3 часа дня", "в 7 вечера"
+				for i+1 < len(tokens) && naturalFillers[tokens[i+1]] {
+					i++
+				}
+				if i+1 < len(tokens) {
+					if pm, ok := naturalMeridiem[tokens[i+1]]; ok {
+						hour = applyMeridiem(hour, pm)
+						i++
+					}
+				}
+				continue
+			}
+			return time.Time{}, fmt.Errorf("unknown token %q", token)
+		}
+	}
+
+	if hour < 0 {
+		return time.Time{}, fmt.Errorf("time of day is missing")
+	}
+	if hour > 23 || minute > 59 {
+		return time.Time{}, fmt.Errorf("invalid time %02d:%02d", hour, minute)
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	switch {
+	case hasDate:
+		// Explicit date wins
+	case hasDay:
+		days := (int(weekday) - int(now.Weekday()) + 7) % 7
+		if nextWeek && days == 0 {
+			days = 7
+		}
+		date = today.AddDate(0, 0, days)
+		// Today's weekday whose time has already passed means the next week
+		if days == 0 && !time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, loc).After(now) {
+			date = date.AddDate(0, 0, 7)
+		}
+	default:
+		// Only a time: today if it's still ahead, otherwise tomorrow
+		date = today
+		if !time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, loc).After(now) {
+			date = date.AddDate(0, 0, 1)
+		}
+	}
+
+	return time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, loc), nil
+}
+
+// naturalDate builds a calendar date, rejecting values that time.Date would roll over, like 31.02
+func naturalDate(year, month, day int, loc *time.Location) (time.Time, error) {
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, loc)
+	if date.Year() != year || int(date.Month()) != month || date.Day() != day {
+		return time.Time{}, fmt.Errorf("invalid date %02d.%02d.%d", day, month, year)
+	}
+	return date, nil
+}
+
+// parseNaturalOffset parses the part after "через"/"in": "2 часа", "полчаса", "an hour", "90 min"
+func parseNaturalOffset(tokens []string) (time.Duration, error) {
+	if len(tokens) == 1 && tokens[0] == "полчаса" {
+		return 30 * time.Minute, nil
+	}
+
+	amount := 1
+	if len(tokens) == 2 {
+		if tokens[0] != "a" && tokens[0] != "an" {
+			n, err := strconv.Atoi(tokens[0])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid amount %q", tokens[0])
+			}
+			amount = n
+		}
+		tokens = tokens[1:]
+	}
+
+	if len(tokens) != 1 {
+		return 0, fmt.Errorf("invalid relative time")
+	}
+	unit, ok := naturalUnits[tokens[0]]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", tokens[0])
+	}
+	return time.Duration(amount) * unit, nil
+}
+
+// applyMeridiem converts a 12-hour clock value to 24-hour
+func applyMeridiem(hour int, pm bool) int {
+	if hour > 12 {
+		return hour
+	}
+	if pm && hour < 12 {
+		return hour + 12
+	}
+	if !pm && hour == 12 {
+		return 0
+	}
+	return hour
+}
+
+// loadLocation loads a timezone by name, falling back to the default Moscow timezone
+func loadLocation(name string) *time.Location {
+	if name == "" {
+		name = DefaultTimezone
+	}
+	if loc, err := time.LoadLocation(name); err == nil {
+		return loc
+	}
+	if loc, err := time.LoadLocation(DefaultTimezone); err == nil {
+		return loc
+	}
+	// Fallback to UTC+3 if location loading fails
+	return time.FixedZone("MSK", 3*60*60)
+}
+
+// userLocation returns the timezone from the user's Mattermost profile, or the default one
+func userLocation(user *model.User) *time.Location {
+	if user == nil {
+		return loadLocation("")
+	}
+	return loadLocation(user.GetPreferredTimezone())
+}
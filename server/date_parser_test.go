@@ -0,0 +1,108 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNaturalDateTime(t *testing.T) {
+	loc := loadLocation("Europe/Moscow")
+	// Wednesday noon
+	now := time.Date(2025, time.January, 15, 12, 0, 0, 0, loc)
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2025, month, day, hour, minute, 0, 0, loc)
+	}
+
+	for _, tc := range []struct {
+		input string
+		want  time.Time
+	}{
+		// Relative days
+		{"завтра в 15:00", at(time.January, 16, 15, 0)},
+		{"послезавтра 9:30", at(time.January, 17, 9, 30)},
+		{"сегодня в 18:00", at(time.January, 15, 18, 0)},
+		// An explicit day keeps a passed time, so the caller rejects it as being in the past
+		{"сегодня в 10:00", at(time.January, 15, 10, 0)},
+		{"tomorrow 3pm", at(time.January, 16, 15, 0)},
+		{"Завтра, в 15:00!", at(time.January, 16, 15, 0)},
+
+		// Only a time: today if still ahead, tomorrow otherwise
+		{"в 15:00", at(time.January, 15, 15, 0)},
+		{"в 11:00", at(time.January, 16, 11, 0)},
+		{"в 12:00", at(time.January, 16, 12, 0)},
+		{"полдень", at(time.January, 16, 12, 0)},
+
+		// Weekdays
+		{"в пятницу 11:30", at(time.January, 17, 11, 30)},
+		{"в среду 13:00", at(time.January, 15, 13, 0)},
+		{"в среду 10:00", at(time.January, 22, 10, 0)},
+		{"next wednesday 13:00", at(time.January, 22, 13, 0)},
+		{"next monday 10:00", at(time.January, 20, 10, 0)},
+
+		// Meridiem words
+		{"завтра в 3 часа дня", at(time.January, 16, 15, 0)},
+		{"завтра в 7 вечера", at(time.January, 16, 19, 0)},
+		{"завтра в 12 ночи", at(time.January, 16, 0, 0)},
+		{"tomorrow 12am", at(time.January, 16, 0, 0)},
+		{"tomorrow 9:15pm", at(time.January, 16, 21, 15)},
+
+		// Relative offsets
+		{"через 2 часа", at(time.January, 15, 14, 0)},
+		{"через полчаса", at(time.January, 15, 12, 30)},
+		{"через 45 минут", at(time.January, 15, 12, 45)},
+		{"in an hour", at(time.January, 15, 13, 0)},
+		{"in 3 days", at(time.January, 18, 12, 0)},
+
+		// Dotted and ISO dates
+		{"20.01 15:00", at(time.January, 20, 15, 0)},
+		{"20.01.2025 в 15:00", at(time.January, 20, 15, 0)},
+		{"10.01 15:00", time.Date(2026, time.January, 10, 15, 0, 0, 0, loc)},
+		{"29.02.2028 10:00", time.Date(2028, time.February, 29, 10, 0, 0, 0, loc)},
+		{"2025-01-20 15:00", at(time.January, 20, 15, 0)},
+		{"2025-01-20T15:00", at(time.January, 20, 15, 0)},
+
+		// Dotted times
+		{"завтра в 11.30", at(time.January, 16, 11, 30)},
+		{"в 15.45", at(time.January, 15, 15, 45)},
+		{"в пятницу 9.05", at(time.January, 17, 9, 5)},
+		{"20.01 в 15.30", at(time.January, 20, 15, 30)},
+		{"завтра 18.00", at(time.January, 16, 18, 0)},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := parseNaturalDateTime(tc.input, now, loc)
+			require.NoError(t, err)
+			assert.True(t, tc.want.Equal(got), "want %s, got %s", tc.want, got)
+		})
+	}
+}
+
+func TestParseNaturalDateTimeRejectsInvalidInput(t *testing.T) {
+	loc := loadLocation("Europe/Moscow")
+	now := time.Date(2025, time.January, 15, 12, 0, 0, 0, loc)
+
+	for _, input := range []string{
+		"",
+		"завтра",
+		"31.02 10:00",
+		"31.04.2025 10:00",
+		"2025-02-30 10:00",
+		"2025-13-01 10:00",
+		"29.02 10:00", // 2025 has no 29 February
+		"00.01 10:00",
+		"завтра в 25:00",
+		"завтра в 10:75",
+		"через 0 минут",
+		"через -2 часа",
+		"через 2 лет",
+		"через неделю 0",
+		"когда-нибудь",
+	} {
+		t.Run(input, func(t *testing.T) {
+			_, err := parseNaturalDateTime(input, now, loc)
+			assert.Error(t, err)
+		})
+	}
+}
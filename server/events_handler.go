@@ -0,0 +1,254 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+)
+
+// Inbound provider events
+const (
+	EventsPath             = "/api/events"
+	EventMeetingStarted    = "meeting_started"
+	EventParticipantJoined = "participant_joined"
+	EventParticipantLeft   = "participant_left"
+	EventMeetingEnded      = "meeting_ended"
+	EventRecordingReady    = "recording_ready"
+	EventTranscriptReady   = "transcript_ready"
+	maxEventBodyBytes      = 10 << 20 // Transcripts may be delivered inline
+)
+
+// Delivered event IDs are remembered so redeliveries aren't applied twice
+const (
+	kvEventPrefix = "event_"
+	eventIDTTL    = 24 * time.Hour
+)
+
+// MeetingEvent is a lifecycle event delivered by n8n or Kontur.Talk to /api/events
+type MeetingEvent struct {
+	EventID          string `json:"event_id"` // Unique per event and stable across redeliveries; required for counted joins and leaves
+	Event            string `json:"event"`
+	MeetingID        string `json:"meeting_id"`
+	Timestamp        string `json:"timestamp"`         // RFC3339, defaults to the time of delivery
+	ParticipantCount *int   `json:"participant_count"` // Current number of people in the call, if known
+	RecordingURL     string `json:"recording_url"`
+	TranscriptURL    string `json:"transcript_url"`
+	TranscriptText   string `json:"transcript_text"` // Attached to the thread reply as a file if AttachTranscript is on
+}
+
+// handleMeetingEvent handles POST /api/events. Like the creation callback it is not called by a Mattermost
+// user: the request must be signed with the webhook secret (X-Kontur-Signature, X-Kontur-Timestamp).
+func (p *Plugin) handleMeetingEvent(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, RequestFieldGeneral, "Method not allowed. Use POST.")
+		return
+	}
+
+	secret := p.getConfiguration().WebhookSecret
+	if secret == "" {
+		p.API.LogWarn("[Kontur] Meeting event rejected: webhook secret is not configured")
+		writeErrorResponse(w, http.StatusForbidden, RequestFieldGeneral, "Events require WebhookSecret to be configured")
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxEventBodyBytes))
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, RequestFieldGeneral, "Failed to read request")
+		return
+	}
+	if err := verifySignature(secret, r.Header.Get(HeaderKonturTimestamp), r.Header.Get(HeaderKonturSignature), body, time.Now()); err != nil {
+		p.API.LogWarn("[Kontur] Meeting event rejected", "error", err.Error())
+		writeErrorResponse(w, http.StatusUnauthorized, RequestFieldGeneral, "Invalid signature")
+		return
+	}
+
+	var event MeetingEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, RequestFieldGeneral, "Invalid JSON: "+err.Error())
+		return
+	}
+
+	at := time.Now()
+	if event.Timestamp != "" {
+		if parsed, err := time.Parse(time.RFC3339, event.Timestamp); err == nil {
+			at = parsed
+		}
+	}
+
+	var apply func(meeting *Meeting) bool
+	switch event.Event {
+	case EventMeetingStarted:
+		apply = func(meeting *Meeting) bool {
+			if meeting.LiveStatus != "" {
+				return false
+			}
+			meeting.LiveStatus = LiveStatusStarted
+			meeting.StartedAt = model.GetMillisForTime(at)
+			meeting.ParticipantCount = eventParticipantCount(event, meeting.ParticipantCount)
+			return true
+		}
+	case EventParticipantJoined, EventParticipantLeft:
+		// Identical joins may legitimately arrive within one second, so only event_id tells a redelivery apart
+		if event.EventID == "" && event.ParticipantCount == nil {
+			writeErrorResponse(w, http.StatusBadRequest, "event_id", "event_id or participant_count is required")
+			return
+		}
+		apply = func(meeting *Meeting) bool {
+			if meeting.HasEnded() {
+				return false
+			}
+			// A join before meeting_started means the call has begun
+			if meeting.LiveStatus == "" {
+				meeting.LiveStatus = LiveStatusStarted
+				meeting.StartedAt = model.GetMillisForTime(at)
+			}
+			delta := 1
+			if event.Event == EventParticipantLeft {
+				delta = -1
+			}
+			meeting.ParticipantCount = eventParticipantCount(event, meeting.ParticipantCount+delta)
+			return true
+		}
+	case EventMeetingEnded:
+		apply = func(meeting *Meeting) bool {
+			if meeting.HasEnded() {
+				return false
+			}
+			if meeting.StartedAt == 0 {
+				meeting.StartedAt = meeting.StartAt
+			}
+			meeting.LiveStatus = LiveStatusEnded
+			meeting.EndedAt = model.GetMillisForTime(at)
+			meeting.ParticipantCount = 0
+			return true
+		}
+	case EventRecordingReady:
+		if event.RecordingURL == "" {
+			writeErrorResponse(w, http.StatusBadRequest, "recording_url", "recording_url is required")
+			return
+		}
+		apply = func(meeting *Meeting) bool {
+			if meeting.RecordingURL == event.RecordingURL {
+				return false
+			}
+			meeting.RecordingURL = event.RecordingURL
+			return true
+		}
+	case EventTranscriptReady:
+		if event.TranscriptURL == "" && event.TranscriptText == "" {
+			writeErrorResponse(w, http.StatusBadRequest, "transcript_url", "transcript_url or transcript_text is required")
+			return
+		}
+		apply = func(meeting *Meeting) bool {
+			if meeting.TranscriptReady {
+				return false
+			}
+			meeting.TranscriptReady = true
+			meeting.TranscriptURL = event.TranscriptURL
+			return true
+		}
+	default:
+		writeErrorResponse(w, http.StatusBadRequest, "event", "Unknown event: "+event.Event)
+		return
+	}
+
+	// Counted joins and leaves must not be applied twice; the other events are checked against the meeting state
+	claimKey := ""
+	if event.EventID != "" {
+		key, claimed, err := p.claimMeetingEvent(event)
+		if err != nil {
+			p.API.LogError("[Kontur] Failed to record meeting event", "meeting_id", event.MeetingID, "event", event.Event, "error", err.Error())
+			writeErrorResponse(w, http.StatusInternalServerError, RequestFieldGeneral, "Failed to save meeting")
+			return
+		}
+		if !claimed {
+			p.API.LogDebug("[Kontur] Duplicate meeting event ignored", "meeting_id", event.MeetingID, "event", event.Event)
+			writeCallbackResponse(w, "ignored")
+			return
+		}
+		claimKey = key
+	}
+
+	changed := false
+	meeting, err := p.modifyMeeting(event.MeetingID, func(meeting *Meeting) bool {
+		if meeting.IsCancelled() {
+			return false
+		}
+		changed = apply(meeting)
+		return changed
+	})
+	if err != nil {
+		// Let the sender's retry through
+		if claimKey != "" {
+			if appErr := p.API.KVDelete(claimKey); appErr != nil {
+				p.API.LogWarn("[Kontur] Failed to release meeting event", "meeting_id", event.MeetingID, "error", appErr.Error())
+			}
+		}
+		if err == ErrMeetingNotFound {
+			writeErrorResponse(w, http.StatusNotFound, RequestFieldMeetingID, "Meeting not found")
+			return
+		}
+		p.API.LogError("[Kontur] Failed to apply meeting event", "meeting_id", event.MeetingID, "event", event.Event, "error", err.Error())
+		writeErrorResponse(w, http.StatusInternalServerError, RequestFieldGeneral, "Failed to save meeting")
+		return
+	}
+
+	if !changed {
+		// Duplicate or out-of-order delivery
+		writeCallbackResponse(w, "ignored")
+		return
+	}
+
+	if event.Event != EventTranscriptReady {
+		if err := p.updateMeetingPost(meeting); err != nil {
+			p.API.LogWarn("[Kontur] Failed to update meeting post", "meeting_id", meeting.ID, "error", err.Error())
+		}
+	}
+
+	// Recordings and transcripts are also announced in the meeting thread
+	switch event.Event {
+	case EventRecordingReady:
+		if err := p.postRecordingLink(meeting); err != nil {
+			p.API.LogWarn("[Kontur] Failed to post recording link", "meeting_id", meeting.ID, "error", err.Error())
+		}
+	case EventTranscriptReady:
+		if err := p.postTranscript(meeting, event.TranscriptText); err != nil {
+			p.API.LogWarn("[Kontur] Failed to post transcript", "meeting_id", meeting.ID, "error", err.Error())
+		}
+	}
+
+	p.API.LogDebug("[Kontur] Meeting event applied", "meeting_id", meeting.ID, "event", event.Event)
+	writeCallbackResponse(w, "success")
+}
+
+// claimMeetingEvent records the event_id in the KV store and reports whether the event is delivered for the first time
+func (p *Plugin) claimMeetingEvent(event MeetingEvent) (string, bool, error) {
+	digest := sha256.Sum256([]byte("id:" + event.EventID))
+	key := kvEventPrefix + hex.EncodeToString(digest[:])
+
+	claimed, appErr := p.API.KVSetWithOptions(key, []byte(event.MeetingID), model.PluginKVSetOptions{
+		Atomic:          true,
+		OldValue:        nil,
+		ExpireInSeconds: int64(eventIDTTL / time.Second),
+	})
+	if appErr != nil {
+		return "", false, appErr
+	}
+	return key, claimed, nil
+}
+
+// eventParticipantCount prefers the count reported by the provider over the locally tracked one
+func eventParticipantCount(event MeetingEvent, tracked int) int {
+	if event.ParticipantCount != nil {
+		tracked = *event.ParticipantCount
+	}
+	if tracked < 0 {
+		return 0
+	}
+	return tracked
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testWebhookSecret = "secret"
+
+// newEventTestPlugin stores a scheduled meeting and configures the webhook secret events are signed with
+func newEventTestPlugin(t *testing.T) *Plugin {
+	p, _ := newTestPlugin(t)
+	p.configuration.WebhookSecret = testWebhookSecret
+	require.NoError(t, p.updateMeeting(&Meeting{ID: "meeting1", Status: MeetingStatusScheduled, ChannelID: "channel", OrganizerID: "organizer"}))
+	return p
+}
+
+// deliverEvent posts the body to /api/events signed with the given timestamp
+func deliverEvent(p *Plugin, body string, at time.Time) *httptest.ResponseRecorder {
+	timestamp := signatureTimestamp(at)
+	r := httptest.NewRequest(http.MethodPost, EventsPath, strings.NewReader(body))
+	r.Header.Set(HeaderKonturTimestamp, timestamp)
+	r.Header.Set(HeaderKonturSignature, signPayload(testWebhookSecret, timestamp, []byte(body)))
+	w := httptest.NewRecorder()
+	p.ServeHTTP(nil, w, r)
+	return w
+}
+
+func participantCount(t *testing.T, p *Plugin) int {
+	meeting, err := p.getMeeting("meeting1")
+	require.NoError(t, err)
+	return meeting.ParticipantCount
+}
+
+func TestMeetingEventReplayIsIgnored(t *testing.T) {
+	p := newEventTestPlugin(t)
+	body := `{"event_id":"evt-1","event":"participant_joined","meeting_id":"meeting1"}`
+	now := time.Now()
+
+	first := deliverEvent(p, body, now)
+	replay := deliverEvent(p, body, now)
+
+	require.Equal(t, http.StatusOK, first.Code)
+	assert.Contains(t, first.Body.String(), `"success"`)
+	require.Equal(t, http.StatusOK, replay.Code)
+	assert.Contains(t, replay.Body.String(), `"ignored"`)
+	assert.Equal(t, 1, participantCount(t, p))
+}
+
+func TestMeetingEventRedeliveryWithSameEventIDIsIgnored(t *testing.T) {
+	p := newEventTestPlugin(t)
+	body := `{"event_id":"evt-1","event":"participant_joined","meeting_id":"meeting1"}`
+	now := time.Now()
+
+	deliverEvent(p, body, now)
+	// A redelivery is signed anew, so only event_id tells it apart
+	redelivery := deliverEvent(p, body, now.Add(time.Minute))
+
+	assert.Contains(t, redelivery.Body.String(), `"ignored"`)
+	assert.Equal(t, 1, participantCount(t, p))
+}
+
+func TestMeetingEventIdenticalJoinsInSameSecondAreCounted(t *testing.T) {
+	p := newEventTestPlugin(t)
+	now := time.Now().Truncate(time.Second)
+
+	// Two people join at once: the bodies and signatures only differ by event_id
+	first := deliverEvent(p, `{"event_id":"evt-1","event":"participant_joined","meeting_id":"meeting1","timestamp":"2025-01-20T15:02:00Z"}`, now)
+	second := deliverEvent(p, `{"event_id":"evt-2","event":"participant_joined","meeting_id":"meeting1","timestamp":"2025-01-20T15:02:00Z"}`, now)
+
+	assert.Contains(t, first.Body.String(), `"success"`)
+	assert.Contains(t, second.Body.String(), `"success"`)
+	assert.Equal(t, 2, participantCount(t, p))
+}
+
+func TestMeetingEventJoinWithoutEventIDIsRejected(t *testing.T) {
+	p := newEventTestPlugin(t)
+
+	w := deliverEvent(p, `{"event":"participant_joined","meeting_id":"meeting1"}`, time.Now())
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `event_id`)
+	assert.Equal(t, 0, participantCount(t, p))
+}
+
+func TestMeetingEventDistinctEventsAreCounted(t *testing.T) {
+	p := newEventTestPlugin(t)
+	now := time.Now()
+
+	deliverEvent(p, `{"event_id":"evt-1","event":"participant_joined","meeting_id":"meeting1"}`, now)
+	deliverEvent(p, `{"event_id":"evt-2","event":"participant_joined","meeting_id":"meeting1"}`, now)
+	deliverEvent(p, `{"event_id":"evt-3","event":"participant_joined","meeting_id":"meeting1"}`, now)
+	deliverEvent(p, `{"event_id":"evt-4","event":"participant_left","meeting_id":"meeting1"}`, now)
+	// A reported count needs no event_id: applying it twice changes nothing
+	deliverEvent(p, `{"event":"participant_joined","meeting_id":"meeting1","participant_count":2}`, now)
+	deliverEvent(p, `{"event":"participant_joined","meeting_id":"meeting1","participant_count":2}`, now)
+
+	assert.Equal(t, 2, participantCount(t, p))
+}
+
+func TestMeetingEventForUnknownMeetingCanBeRetried(t *testing.T) {
+	p := newEventTestPlugin(t)
+	body := `{"event_id":"evt-1","event":"participant_joined","meeting_id":"missing"}`
+	now := time.Now()
+
+	assert.Equal(t, http.StatusNotFound, deliverEvent(p, body, now).Code)
+	assert.Equal(t, http.StatusNotFound, deliverEvent(p, body, now).Code)
+}
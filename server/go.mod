@@ -2,7 +2,10 @@ module github.com/skyeng/mattermost-kontur-meeting-plugin
 
 go 1.19
 
-require github.com/mattermost/mattermost-server/v6 v6.7.2
+require (
+	github.com/mattermost/mattermost-server/v6 v6.7.2
+	github.com/stretchr/testify v1.7.1
+)
 
 require (
 	github.com/blang/semver v3.5.1+incompatible // indirect
@@ -45,7 +48,7 @@ require (
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/rs/xid v1.4.0 // indirect
 	github.com/sirupsen/logrus v1.8.1 // indirect
-	github.com/stretchr/testify v1.7.1 // indirect
+	github.com/stretchr/objx v0.3.0 // indirect
 	github.com/tinylib/msgp v1.1.6 // indirect
 	github.com/vmihailenco/msgpack/v5 v5.3.5 // indirect
 	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
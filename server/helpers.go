@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/mattermost/mattermost-server/v6/model"
 )
@@ -84,6 +85,25 @@ func (p *Plugin) getChannelSafely(channelID string) (*model.Channel, error) {
 	return nil, fmt.Errorf("channel not found: %s", channelID)
 }
 
+// resolveActingUserID returns the authenticated user ID, rejecting a body user_id that names someone else
+func resolveActingUserID(authUserID, bodyUserID string) (string, error) {
+	if authUserID == "" {
+		return "", fmt.Errorf("request is not authenticated")
+	}
+	if bodyUserID != "" && bodyUserID != authUserID {
+		return "", fmt.Errorf("user_id %s does not match authenticated user %s", bodyUserID, authUserID)
+	}
+	return authUserID, nil
+}
+
+// siteURL returns the Mattermost Site URL without the trailing slash, or "" if it isn't configured
+func (p *Plugin) siteURL() string {
+	if config := p.API.GetConfig(); config != nil && config.ServiceSettings.SiteURL != nil {
+		return strings.TrimRight(*config.ServiceSettings.SiteURL, "/")
+	}
+	return ""
+}
+
 // writeErrorResponse writes a standardized error response
 func writeErrorResponse(w http.ResponseWriter, status int, field, message string) {
 	w.Header().Set("Content-Type", "application/json")
@@ -97,3 +117,19 @@ func writeErrorResponse(w http.ResponseWriter, status int, field, message string
 	json.NewEncoder(w).Encode(response)
 }
 
+
+// pluralRu picks the Russian plural form for n: 1 участник, 2 участника, 5 участников
+func pluralRu(n int, one, few, many string) string {
+	n %= 100
+	if n >= 11 && n <= 14 {
+		return many
+	}
+	switch n % 10 {
+	case 1:
+		return one
+	case 2, 3, 4:
+		return few
+	default:
+		return many
+	}
+}
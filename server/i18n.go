@@ -0,0 +1,583 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Supported locales of server messages
+const (
+	LocaleRu = "ru"
+	LocaleEn = "en"
+
+	// DefaultLocale is used for users whose Mattermost locale isn't supported
+	DefaultLocale = LocaleRu
+)
+
+// messages holds the server message catalog per locale. Every key must exist in each locale;
+// plural forms use the .one, .few and .many suffixes (English repeats .many in .few).
+var messages = map[string]map[string]string{
+	LocaleRu: {
+		// Request handling
+		"request.unauthorized":       "Требуется авторизация в Mattermost",
+		"request.read_failed":        "Не удалось прочитать запрос",
+		"request.invalid_json":       "Неверный формат JSON: %s",
+		"request.user_mismatch":      "user_id не совпадает с текущим пользователем",
+		"request.method_not_allowed": "Метод не разрешён. Используйте POST.",
+		"request.internal_error":     "Внутренняя ошибка сервера: %v",
+
+		// Field validation
+		"validation.channel_required":   "channel_id обязателен",
+		"validation.duration_too_short": "Продолжительность должна быть не менее %d минут",
+		"validation.duration_too_long":  "Продолжительность не может превышать %d минут (%d часов)",
+		"validation.title_too_long":     "Название не может быть длиннее %d символов",
+
+		// Date and time
+		"datetime.invalid_local": "неверный формат локального времени: %s",
+		"datetime.invalid":       "неверный формат даты и времени: %s",
+		"datetime.required":      "дата и время обязательны",
+		"datetime.in_past":       "дата и время не могут быть в прошлом",
+		"datetime.too_far":       "дата не может быть более чем через %d дней",
+		"datetime.layout":        "02.01.2006, 15:04",
+		"datetime.layout_clock":  "15:04",
+		"datetime.layout_short":  "02.01 15:04",
+		"datetime.layout_date":   "02.01.2006",
+		"datetime.moscow":        "по МСК",
+		"datetime.msk":           "МСК",
+
+		// Scheduling
+		"schedule.user_not_found":           "Пользователь не найден: %s",
+		"schedule.channel_not_found":        "Канал не найден: %s",
+		"schedule.participants_required":    "необходимо выбрать хотя бы одного участника",
+		"schedule.participants_unavailable": "не удалось получить информацию об участниках",
+		"schedule.success":                  "Встреча успешно создана",
+		"schedule.pending":                  "Встреча создаётся, ссылка появится в канале",
+		"schedule.save_failed":              "Не удалось сохранить встречу",
+
+		// Slot suggestions
+		"slots.duration_range":        "Продолжительность должна быть от %d до %d минут",
+		"slots.too_many_participants": "Можно указать не более %d участников",
+		"slots.invalid_workday":       "Рабочие часы указываются как ЧЧ:ММ, и встреча должна в них помещаться",
+		"slots.invalid_step":          "Шаг должен быть не меньше %d минут и делить сутки без остатка: 5, 10, 15, 20, 30, 45, 60…",
+		"slots.invalid_window":        "Интервал поиска должен заканчиваться позже начала и не позднее чем через %d дней",
+		"slots.invalid_date":          "неверный формат даты: %s",
+
+		// Provider and webhook failures
+		"provider.unsupported":   "Провайдер встреч «%s» не поддерживается. Обратитесь к администратору.",
+		"provider.create_failed": "Не удалось создать встречу",
+		"provider.update_failed": "Не удалось изменить встречу",
+		"provider.cancel_failed": "Не удалось отменить встречу",
+		"webhook.error_status":   "Ошибка при создании встречи (статус %d)",
+		"webhook.no_room_url":    "Вебхук не вернул ссылку на комнату. Встреча не была создана.",
+		"webhook.unreachable":    "🔌 Не удалось подключиться к вебхуку n8n.\n\nПроверьте:\n1. n8n запущен и доступен\n2. Workflow активирован\n3. URL указан правильно",
+
+		// Meeting post
+		"meeting.default_title":  "Встреча",
+		"post.failed":            "❌ Не удалось создать встречу «%s» на %s",
+		"post.cancelled":         "❌ Встреча «%s» на %s отменена",
+		"post.pending":           "⏳ @%s создаёт встречу на %s",
+		"post.scheduled":         "📅 @%s запланировал встречу на %s",
+		"post.participants":      "👥 Участники: %s",
+		"post.field.start":       "🕐 Начало",
+		"post.field.duration":    "⏱ Длительность",
+		"post.field.organizer":   "👤 Организатор",
+		"post.field.recurrence":  "🔁 Повтор",
+		"post.field.status":      "📡 Статус",
+		"post.duration":          "%d минут",
+		"post.cancelled_text":    "Встреча отменена.",
+		"post.pending_text":      "Ссылка на комнату появится здесь, когда встреча будет создана.",
+		"post.join_link":         "[🔗 Присоединиться к встрече](%s)",
+		"post.recording_link":    "[🎬 Запись встречи](%s)",
+		"post.button.join":       "🔗 Присоединиться",
+		"post.button.calendar":   "📆 В календарь",
+		"post.button.reschedule": "🕐 Перенести",
+		"post.button.cancel":     "❌ Отменить",
+		"instant.failed":         "❌ Не удалось создать встречу: %s",
+		"instant.cancelled":      "📞 Встреча отменена",
+		"instant.pending_by":     "⏳ @%s создаёт встречу…",
+		"instant.pending":        "⏳ Создаю встречу…",
+		"instant.created_by":     "📞 @%s создал встречу: %s",
+		"instant.created":        "📞 Я создал встречу: %s",
+		"live.started":           "🔴 Идёт сейчас",
+		"live.started_count":     "🔴 Идёт сейчас, %d %s",
+		"live.participants.one":  "участник",
+		"live.participants.few":  "участника",
+		"live.participants.many": "участников",
+		"live.ended":             "✅ Завершена",
+		"live.ended_duration":    "✅ Завершена, длилась %d мин",
+
+		// Recurrence description
+		"recurrence.daily":       "каждый день",
+		"recurrence.every_days":  "каждые %d %s",
+		"recurrence.days.one":    "день",
+		"recurrence.days.few":    "дня",
+		"recurrence.days.many":   "дней",
+		"recurrence.weekdays":    "по будним дням",
+		"recurrence.weekly":      "каждую неделю: %s",
+		"recurrence.every_weeks": "каждые %d %s: %s",
+		"recurrence.weeks.one":   "неделю",
+		"recurrence.weeks.few":   "недели",
+		"recurrence.weeks.many":  "недель",
+		"recurrence.count":       ", %d %s",
+		"recurrence.times.one":   "раз",
+		"recurrence.times.few":   "раза",
+		"recurrence.times.many":  "раз",
+		"recurrence.until":       ", до %s",
+		"weekday.mon":            "пн",
+		"weekday.tue":            "вт",
+		"weekday.wed":            "ср",
+		"weekday.thu":            "чт",
+		"weekday.fri":            "пт",
+		"weekday.sat":            "сб",
+		"weekday.sun":            "вс",
+
+		// Local time notices
+		"notice.starts":      "🕐 Встреча «%s» начнётся %s по вашему времени (%s)",
+		"notice.rescheduled": "🕐 Встреча «%s» перенесена: %s по вашему времени (%s)",
+
+		// Slash command
+		"command.help": "**Команды /meeting**\n\n" +
+			"* `/meeting now` — создать встречу прямо сейчас\n" +
+			"* `/meeting schedule <когда> <длительность> @user... [--force]` — запланировать встречу, например `/meeting schedule завтра в 15:00 30 @ivan @maria`. С `--force` встреча создаётся, даже если участники заняты\n" +
+			"* `/meeting list` — предстоящие встречи канала\n" +
+			"* `/meeting cancel <id> [series]` — отменить встречу или всю серию повторяющихся встреч (организатор или администратор канала)\n" +
+			"* `/meeting help` — эта справка\n\n" +
+			"Время можно указать как `завтра в 15:00`, `в пятницу 11:30`, `через 2 часа`, `tomorrow 3pm`, `next monday 10:00` или `2025-01-20T15:00:00+03:00`.\n" +
+			"Длительность указывается в минутах (`30`) или в формате `1h30m`.",
+		"command.unknown":             "Неизвестная команда: %s",
+		"command.unknown_subcommand":  "Неизвестная подкоманда `%s`.\n\n%s",
+		"command.now_pending":         "⏳ Встреча создаётся, ссылка появится в канале.",
+		"command.now_created":         "✅ Встреча создана: %s",
+		"command.schedule_usage":      "❌ Укажите время и длительность встречи.\n\n%s",
+		"command.user_not_found":      "❌ Пользователь не найден: @%s",
+		"command.force_hint":          "⚠️ %s.\n\nЧтобы создать встречу всё равно, повторите команду с `--force` в конце.",
+		"command.scheduled_pending":   "⏳ Встреча запланирована (id: `%s`), ссылка появится в канале.",
+		"command.scheduled":           "✅ Встреча запланирована (id: `%s`): %s",
+		"command.error":               "❌ %s",
+		"command.list_failed":         "❌ Не удалось получить список встреч",
+		"command.list_empty":          "В этом канале нет запланированных встреч.",
+		"command.list_header":         "**Запланированные встречи:**",
+		"command.list_item":           "* %s — %s, %d мин — %s — id: `%s`",
+		"command.list_untitled":       "Без названия",
+		"command.list_link":           "[ссылка](%s)",
+		"command.list_pending":        "комната создаётся",
+		"command.cancel_usage":        "❌ Укажите id встречи: `/meeting cancel <id>` или `/meeting cancel <id> series`",
+		"command.meeting_not_found":   "❌ Встреча не найдена: `%s`",
+		"command.meeting_load_failed": "❌ Не удалось загрузить встречу",
+		"command.cancel_forbidden":    "❌ Отменить встречу может только организатор или администратор канала",
+		"command.not_recurring":       "❌ Встреча не повторяется.",
+		"command.series_cancelled":    "✅ Серия встреч `%s` отменена.",
+		"command.already_cancelled":   "Встреча уже отменена.",
+		"command.cancelled":           "✅ Встреча `%s` отменена.",
+		"command.invalid_duration":    "неверный формат длительности: %s",
+
+		// Slash command autocomplete
+		"command.autocomplete.meeting":       "Встречи Kontur.Talk",
+		"command.autocomplete.description":   "Создание встреч Kontur.Talk",
+		"command.autocomplete.commands":      "Доступные команды: %s",
+		"command.autocomplete.now":           "Создать встречу прямо сейчас",
+		"command.autocomplete.schedule":      "Запланировать встречу",
+		"command.autocomplete.schedule_hint": "<когда> <длительность> @user... [--force]",
+		"command.autocomplete.list":          "Предстоящие встречи канала",
+		"command.autocomplete.cancel":        "Отменить встречу или серию",
+		"command.autocomplete.help":          "Справка по команде",
+
+		// Post actions
+		"action.not_channel_member": "❌ Вы не являетесь участником канала встречи",
+		"action.error":              "❌ %s",
+		"action.calendar":           "[📆 Скачать .ics](%s) (Outlook, Apple Calendar, Thunderbird) · [Добавить в Google Calendar](%s)",
+		"action.cancelled":          "✅ Встреча отменена",
+		"action.dialog_failed":      "Не удалось открыть окно переноса встречи",
+
+		// Reschedule dialog
+		"dialog.title":            "Перенести встречу",
+		"dialog.submit":           "Перенести",
+		"dialog.start":            "Новое время начала",
+		"dialog.start_help":       "Например: «завтра в 15:00» или «2025-01-20 15:00». Часовой пояс: %s",
+		"dialog.duration":         "Длительность (минуты)",
+		"dialog.invalid_duration": "Укажите длительность в минутах",
+
+		// Access checks
+		"access.not_channel_member":             "вы не являетесь участником этого канала",
+		"access.no_post_permission":             "у вас нет прав на публикацию сообщений в этом канале",
+		"access.participant_not_channel_member": "@%s не является участником канала",
+		"access.participant_not_team_member":    "@%s не является участником команды",
+		"access.not_meeting_channel_member":     "Вы не являетесь участником канала встречи",
+
+		// Meeting management
+		"meeting.not_found":            "Встреча не найдена",
+		"meeting.not_found_id":         "Встреча не найдена: %s",
+		"meeting.load_failed":          "Не удалось загрузить встречу",
+		"meeting.update_forbidden":     "Изменить встречу может только организатор или администратор канала",
+		"meeting.cancel_forbidden":     "Отменить встречу может только организатор или администратор канала",
+		"meeting.reschedule_forbidden": "Перенести встречу может только организатор или администратор канала",
+		"meeting.updated":              "Встреча обновлена",
+		"meeting.unchanged":            "Изменений нет",
+		"meeting.cancelled":            "Встреча отменена",
+		"meeting.already_cancelled":    "Встреча уже отменена",
+		"meeting.ended":                "Встреча уже завершилась",
+		"meeting.pending":              "Встреча ещё создаётся, попробуйте позже",
+		"meeting.not_recurring":        "Встреча не повторяется",
+		"meeting.series_cancelled":     "Серия встреч отменена",
+		"meeting.series_stopped":       "Серия встреч «%s» остановлена, новые встречи не будут создаваться: %s",
+		"meeting.update_save_failed":   "Не удалось сохранить изменения встречи",
+		"meeting.cancel_save_failed":   "Не удалось сохранить отмену встречи",
+		"meeting.series_cancel_failed": "Не удалось отменить серию встреч",
+		"meeting.method_get":           "Метод не разрешён. Используйте GET.",
+		"meeting.method_patch":         "Метод не разрешён. Используйте PATCH.",
+
+		// Schedule conflicts
+		"conflicts.message": "В это время у участников есть другие встречи: %s. Выберите другое время или подтвердите создание встречи",
+		"conflicts.more":    "и ещё %d",
+		"conflicts.busy":    "занят",
+		"conflicts.title":   "«%s»",
+
+		// Recurrence validation
+		"recurrence.unknown_weekday":    "неизвестный день недели: %s",
+		"recurrence.unknown_frequency":  "неизвестная периодичность: %s",
+		"recurrence.invalid_until_date": "неверная дата окончания повторов: %s",
+		"recurrence.invalid_part":       "неверная часть RRULE: %s",
+		"recurrence.unsupported_freq":   "поддерживаются только FREQ=DAILY и FREQ=WEEKLY",
+		"recurrence.invalid_interval":   "неверный INTERVAL: %s",
+		"recurrence.invalid_count":      "неверный COUNT: %s",
+		"recurrence.unsupported_byday":  "неподдерживаемое значение BYDAY: %s",
+		"recurrence.unsupported_part":   "неподдерживаемая часть RRULE: %s",
+		"recurrence.missing_freq":       "в RRULE не указан FREQ",
+		"recurrence.invalid_until":      "неверный UNTIL: %s",
+		"recurrence.interval_range":     "интервал повторения должен быть от 1 до %d",
+		"recurrence.count_range":        "количество повторений должно быть от 1 до %d",
+		"recurrence.until_before_start": "дата окончания повторов раньше первой встречи",
+
+		// Provider configuration and room creation
+		"provider.webhook_not_configured": "Webhook URL не настроен. Обратитесь к администратору.",
+		"provider.template_missing":       "Шаблон ссылки на комнату не настроен. Обратитесь к администратору.",
+		"provider.template_no_random":     "Шаблон ссылки на комнату должен содержать {random}. Обратитесь к администратору.",
+		"provider.template_invalid":       "Шаблон ссылки на комнату задаёт неверный URL. Обратитесь к администратору.",
+		"provider.room_failed":            "Провайдер не смог создать комнату",
+		"provider.room_timeout":           "Провайдер не прислал ссылку на комнату вовремя",
+
+		// Calendar file
+		"ics.description": "Ссылка на встречу: %s",
+		"ics.alarm":       "«%s» через %d мин",
+
+		// Recordings and transcripts
+		"artifacts.recording":       "🎬 Запись встречи «%s» готова: [смотреть запись](%s)",
+		"artifacts.transcript":      "📝 Расшифровка встречи «%s» готова",
+		"artifacts.transcript_link": ": [открыть расшифровку](%s)",
+
+		// Reminders
+		"reminder.message": "⏰ Через %d мин начнётся «%s»%s",
+		"reminder.channel": " в ~%s",
+		"reminder.start":   "🕐 Начало: %s",
+	},
+	LocaleEn: {
+		// Request handling
+		"request.unauthorized":       "Mattermost authentication required",
+		"request.read_failed":        "Could not read the request",
+		"request.invalid_json":       "Invalid JSON: %s",
+		"request.user_mismatch":      "user_id does not match the current user",
+		"request.method_not_allowed": "Method not allowed. Use POST.",
+		"request.internal_error":     "Internal server error: %v",
+
+		// Field validation
+		"validation.channel_required":   "channel_id is required",
+		"validation.duration_too_short": "Duration must be at least %d minutes",
+		"validation.duration_too_long":  "Duration cannot exceed %d minutes (%d hours)",
+		"validation.title_too_long":     "Title cannot be longer than %d characters",
+
+		// Date and time
+		"datetime.invalid_local": "invalid local time format: %s",
+		"datetime.invalid":       "invalid date and time format: %s",
+		"datetime.required":      "date and time are required",
+		"datetime.in_past":       "date and time cannot be in the past",
+		"datetime.too_far":       "date cannot be more than %d days ahead",
+		"datetime.layout":        "Mon, 02 Jan 2006, 3:04 PM",
+		"datetime.layout_clock":  "3:04 PM",
+		"datetime.layout_short":  "02 Jan 3:04 PM",
+		"datetime.layout_date":   "02 Jan 2006",
+		"datetime.moscow":        "MSK",
+		"datetime.msk":           "MSK",
+
+		// Scheduling
+		"schedule.user_not_found":           "User not found: %s",
+		"schedule.channel_not_found":        "Channel not found: %s",
+		"schedule.participants_required":    "select at least one participant",
+		"schedule.participants_unavailable": "could not load the participants",
+		"schedule.success":                  "Meeting created",
+		"schedule.pending":                  "The meeting is being created, the link will appear in the channel",
+		"schedule.save_failed":              "Could not save the meeting",
+
+		// Slot suggestions
+		"slots.duration_range":        "Duration must be between %d and %d minutes",
+		"slots.too_many_participants": "At most %d participants can be specified",
+		"slots.invalid_workday":       "Working hours are given as HH:MM, and the meeting must fit into them",
+		"slots.invalid_step":          "The step must be at least %d minutes and divide a day evenly: 5, 10, 15, 20, 30, 45, 60…",
+		"slots.invalid_window":        "The search window must end after it starts and no later than %d days ahead",
+		"slots.invalid_date":          "invalid date format: %s",
+
+		// Provider and webhook failures
+		"provider.unsupported":   "Meeting provider “%s” is not supported. Contact your administrator.",
+		"provider.create_failed": "Could not create the meeting",
+		"provider.update_failed": "Could not update the meeting",
+		"provider.cancel_failed": "Could not cancel the meeting",
+		"webhook.error_status":   "The meeting service returned an error (status %d)",
+		"webhook.no_room_url":    "The webhook did not return a room link. The meeting was not created.",
+		"webhook.unreachable":    "🔌 Could not reach the n8n webhook.\n\nCheck that:\n1. n8n is running and reachable\n2. The workflow is active\n3. The URL is correct",
+
+		// Meeting post
+		"meeting.default_title":  "Meeting",
+		"post.failed":            "❌ Could not create “%s” at %s",
+		"post.cancelled":         "❌ “%s” at %s has been cancelled",
+		"post.pending":           "⏳ @%s is creating a meeting at %s",
+		"post.scheduled":         "📅 @%s scheduled a meeting at %s",
+		"post.participants":      "👥 Participants: %s",
+		"post.field.start":       "🕐 Start",
+		"post.field.duration":    "⏱ Duration",
+		"post.field.organizer":   "👤 Organizer",
+		"post.field.recurrence":  "🔁 Repeats",
+		"post.field.status":      "📡 Status",
+		"post.duration":          "%d min",
+		"post.cancelled_text":    "The meeting has been cancelled.",
+		"post.pending_text":      "The room link will appear here once the meeting is created.",
+		"post.join_link":         "[🔗 Join the meeting](%s)",
+		"post.recording_link":    "[🎬 Meeting recording](%s)",
+		"post.button.join":       "🔗 Join",
+		"post.button.calendar":   "📆 Add to calendar",
+		"post.button.reschedule": "🕐 Reschedule",
+		"post.button.cancel":     "❌ Cancel",
+		"instant.failed":         "❌ Could not create the meeting: %s",
+		"instant.cancelled":      "📞 The meeting has been cancelled",
+		"instant.pending_by":     "⏳ @%s is creating a meeting…",
+		"instant.pending":        "⏳ Creating a meeting…",
+		"instant.created_by":     "📞 @%s started a meeting: %s",
+		"instant.created":        "📞 I started a meeting: %s",
+		"live.started":           "🔴 In progress",
+		"live.started_count":     "🔴 In progress, %d %s",
+		"live.participants.one":  "participant",
+		"live.participants.few":  "participants",
+		"live.participants.many": "participants",
+		"live.ended":             "✅ Ended",
+		"live.ended_duration":    "✅ Ended, lasted %d min",
+
+		// Recurrence description
+		"recurrence.daily":       "every day",
+		"recurrence.every_days":  "every %d %s",
+		"recurrence.days.one":    "day",
+		"recurrence.days.few":    "days",
+		"recurrence.days.many":   "days",
+		"recurrence.weekdays":    "on weekdays",
+		"recurrence.weekly":      "every week: %s",
+		"recurrence.every_weeks": "every %d %s: %s",
+		"recurrence.weeks.one":   "week",
+		"recurrence.weeks.few":   "weeks",
+		"recurrence.weeks.many":  "weeks",
+		"recurrence.count":       ", %d %s",
+		"recurrence.times.one":   "time",
+		"recurrence.times.few":   "times",
+		"recurrence.times.many":  "times",
+		"recurrence.until":       ", until %s",
+		"weekday.mon":            "Mon",
+		"weekday.tue":            "Tue",
+		"weekday.wed":            "Wed",
+		"weekday.thu":            "Thu",
+		"weekday.fri":            "Fri",
+		"weekday.sat":            "Sat",
+		"weekday.sun":            "Sun",
+
+		// Local time notices
+		"notice.starts":      "🕐 “%s” starts %s your time (%s)",
+		"notice.rescheduled": "🕐 “%s” has been moved to %s your time (%s)",
+
+		// Slash command
+		"command.help": "**/meeting commands**\n\n" +
+			"* `/meeting now` — start a meeting right now\n" +
+			"* `/meeting schedule <when> <duration> @user... [--force]` — schedule a meeting, e.g. `/meeting schedule tomorrow 3pm 30 @ivan @maria`. With `--force` the meeting is created even if participants are busy\n" +
+			"* `/meeting list` — upcoming meetings of the channel\n" +
+			"* `/meeting cancel <id> [series]` — cancel a meeting or a whole recurring series (organizer or channel admin)\n" +
+			"* `/meeting help` — this help\n\n" +
+			"The time can be given as `tomorrow 3pm`, `next monday 10:00`, `in 2 hours`, `завтра в 15:00` or `2025-01-20T15:00:00+03:00`.\n" +
+			"The duration is given in minutes (`30`) or as `1h30m`.",
+		"command.unknown":             "Unknown command: %s",
+		"command.unknown_subcommand":  "Unknown subcommand `%s`.\n\n%s",
+		"command.now_pending":         "⏳ The meeting is being created, the link will appear in the channel.",
+		"command.now_created":         "✅ Meeting created: %s",
+		"command.schedule_usage":      "❌ Specify the meeting time and duration.\n\n%s",
+		"command.user_not_found":      "❌ User not found: @%s",
+		"command.force_hint":          "⚠️ %s.\n\nTo create the meeting anyway, repeat the command with `--force` at the end.",
+		"command.scheduled_pending":   "⏳ Meeting scheduled (id: `%s`), the link will appear in the channel.",
+		"command.scheduled":           "✅ Meeting scheduled (id: `%s`): %s",
+		"command.error":               "❌ %s",
+		"command.list_failed":         "❌ Could not load the meetings",
+		"command.list_empty":          "There are no scheduled meetings in this channel.",
+		"command.list_header":         "**Scheduled meetings:**",
+		"command.list_item":           "* %s — %s, %d min — %s — id: `%s`",
+		"command.list_untitled":       "Untitled",
+		"command.list_link":           "[link](%s)",
+		"command.list_pending":        "room is being created",
+		"command.cancel_usage":        "❌ Specify the meeting id: `/meeting cancel <id>` or `/meeting cancel <id> series`",
+		"command.meeting_not_found":   "❌ Meeting not found: `%s`",
+		"command.meeting_load_failed": "❌ Could not load the meeting",
+		"command.cancel_forbidden":    "❌ Only the organizer or a channel admin can cancel the meeting",
+		"command.not_recurring":       "❌ The meeting does not repeat.",
+		"command.series_cancelled":    "✅ Meeting series `%s` cancelled.",
+		"command.already_cancelled":   "The meeting is already cancelled.",
+		"command.cancelled":           "✅ Meeting `%s` cancelled.",
+		"command.invalid_duration":    "invalid duration format: %s",
+
+		// Slash command autocomplete
+		"command.autocomplete.meeting":       "Kontur.Talk meetings",
+		"command.autocomplete.description":   "Create Kontur.Talk meetings",
+		"command.autocomplete.commands":      "Available commands: %s",
+		"command.autocomplete.now":           "Start a meeting right now",
+		"command.autocomplete.schedule":      "Schedule a meeting",
+		"command.autocomplete.schedule_hint": "<when> <duration> @user... [--force]",
+		"command.autocomplete.list":          "Upcoming meetings in the channel",
+		"command.autocomplete.cancel":        "Cancel a meeting or a series",
+		"command.autocomplete.help":          "Command help",
+
+		// Post actions
+		"action.not_channel_member": "❌ You are not a member of the meeting's channel",
+		"action.error":              "❌ %s",
+		"action.calendar":           "[📆 Download .ics](%s) (Outlook, Apple Calendar, Thunderbird) · [Add to Google Calendar](%s)",
+		"action.cancelled":          "✅ The meeting is cancelled",
+		"action.dialog_failed":      "Could not open the reschedule dialog",
+
+		// Reschedule dialog
+		"dialog.title":            "Reschedule meeting",
+		"dialog.submit":           "Reschedule",
+		"dialog.start":            "New start time",
+		"dialog.start_help":       "For example: “tomorrow 3pm” or “2025-01-20 15:00”. Time zone: %s",
+		"dialog.duration":         "Duration (minutes)",
+		"dialog.invalid_duration": "Enter the duration in minutes",
+
+		// Access checks
+		"access.not_channel_member":             "you are not a member of this channel",
+		"access.no_post_permission":             "you are not allowed to post in this channel",
+		"access.participant_not_channel_member": "@%s is not a member of the channel",
+		"access.participant_not_team_member":    "@%s is not a member of the team",
+		"access.not_meeting_channel_member":     "You are not a member of the meeting's channel",
+
+		// Meeting management
+		"meeting.not_found":            "Meeting not found",
+		"meeting.not_found_id":         "Meeting not found: %s",
+		"meeting.load_failed":          "Could not load the meeting",
+		"meeting.update_forbidden":     "Only the organizer or a channel admin can change the meeting",
+		"meeting.cancel_forbidden":     "Only the organizer or a channel admin can cancel the meeting",
+		"meeting.reschedule_forbidden": "Only the organizer or a channel admin can reschedule the meeting",
+		"meeting.updated":              "The meeting is updated",
+		"meeting.unchanged":            "Nothing changed",
+		"meeting.cancelled":            "The meeting is cancelled",
+		"meeting.already_cancelled":    "The meeting is already cancelled",
+		"meeting.ended":                "The meeting has already ended",
+		"meeting.pending":              "The meeting is still being created, try again later",
+		"meeting.not_recurring":        "The meeting does not repeat",
+		"meeting.series_cancelled":     "The meeting series is cancelled",
+		"meeting.series_stopped":       "The meeting series “%s” is stopped and no new meetings will be created: %s",
+		"meeting.update_save_failed":   "Could not save the meeting changes",
+		"meeting.cancel_save_failed":   "Could not save the meeting cancellation",
+		"meeting.series_cancel_failed": "Could not cancel the meeting series",
+		"meeting.method_get":           "Method not allowed. Use GET.",
+		"meeting.method_patch":         "Method not allowed. Use PATCH.",
+
+		// Schedule conflicts
+		"conflicts.message": "Participants have other meetings at this time: %s. Choose another time or confirm creating the meeting",
+		"conflicts.more":    "and %d more",
+		"conflicts.busy":    "busy",
+		"conflicts.title":   "“%s”",
+
+		// Recurrence validation
+		"recurrence.unknown_weekday":    "unknown weekday: %s",
+		"recurrence.unknown_frequency":  "unknown frequency: %s",
+		"recurrence.invalid_until_date": "invalid end date of the recurrence: %s",
+		"recurrence.invalid_part":       "invalid RRULE part: %s",
+		"recurrence.unsupported_freq":   "only FREQ=DAILY and FREQ=WEEKLY are supported",
+		"recurrence.invalid_interval":   "invalid INTERVAL: %s",
+		"recurrence.invalid_count":      "invalid COUNT: %s",
+		"recurrence.unsupported_byday":  "unsupported BYDAY value: %s",
+		"recurrence.unsupported_part":   "unsupported RRULE part: %s",
+		"recurrence.missing_freq":       "RRULE has no FREQ",
+		"recurrence.invalid_until":      "invalid UNTIL: %s",
+		"recurrence.interval_range":     "the recurrence interval must be between 1 and %d",
+		"recurrence.count_range":        "the number of occurrences must be between 1 and %d",
+		"recurrence.until_before_start": "the recurrence ends before the first meeting",
+
+		// Provider configuration and room creation
+		"provider.webhook_not_configured": "Webhook URL is not configured. Contact your administrator.",
+		"provider.template_missing":       "The room link template is not configured. Contact your administrator.",
+		"provider.template_no_random":     "The room link template must contain {random}. Contact your administrator.",
+		"provider.template_invalid":       "The room link template produces an invalid URL. Contact your administrator.",
+		"provider.room_failed":            "The provider could not create the room",
+		"provider.room_timeout":           "The provider did not send the room link in time",
+
+		// Calendar file
+		"ics.description": "Meeting link: %s",
+		"ics.alarm":       "“%s” in %d min",
+
+		// Recordings and transcripts
+		"artifacts.recording":       "🎬 The recording of “%s” is ready: [watch the recording](%s)",
+		"artifacts.transcript":      "📝 The transcript of “%s” is ready",
+		"artifacts.transcript_link": ": [open the transcript](%s)",
+
+		// Reminders
+		"reminder.message": "⏰ “%[2]s” starts in %[1]d min%[3]s",
+		"reminder.channel": " in ~%s",
+		"reminder.start":   "🕐 Starts: %s",
+	},
+}
+
+// weekdayKeys maps weekdays to their short name keys in the catalog
+var weekdayKeys = map[time.Weekday]string{
+	time.Monday: "weekday.mon", time.Tuesday: "weekday.tue", time.Wednesday: "weekday.wed", time.Thursday: "weekday.thu",
+	time.Friday: "weekday.fri", time.Saturday: "weekday.sat", time.Sunday: "weekday.sun",
+}
+
+// normalizeLocale maps a Mattermost locale such as "en" or "pt-BR", or an Accept-Language
+// header such as "en-US,en;q=0.9", to a supported one
+func normalizeLocale(locale string) string {
+	if i := strings.IndexAny(locale, ",;"); i >= 0 {
+		locale = locale[:i]
+	}
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	for supported := range messages {
+		if locale == supported || strings.HasPrefix(locale, supported+"-") || strings.HasPrefix(locale, supported+"_") {
+			return supported
+		}
+	}
+	return DefaultLocale
+}
+
+// tr returns the message for the key in the locale, formatted with args if any.
+// Missing keys fall back to the default locale and then to the key itself.
+func tr(locale, key string, args ...interface{}) string {
+	text, ok := messages[normalizeLocale(locale)][key]
+	if !ok {
+		if text, ok = messages[DefaultLocale][key]; !ok {
+			text = key
+		}
+	}
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+// trPlural returns the plural form of the key for n: "участник", "участника", "участников"
+func trPlural(locale, key string, n int) string {
+	if normalizeLocale(locale) == LocaleRu {
+		return tr(locale, pluralRu(n, key+".one", key+".few", key+".many"))
+	}
+	if n == 1 {
+		return tr(locale, key+".one")
+	}
+	return tr(locale, key+".many")
+}
+
+// userLocale returns the supported locale from the user's Mattermost profile
+func (p *Plugin) userLocale(userID string) string {
+	user, err := p.getUserSafely(userID)
+	if err != nil {
+		return DefaultLocale
+	}
+	return normalizeLocale(user.Locale)
+}
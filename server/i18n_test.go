@@ -0,0 +1,38 @@
+package main
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// formatVerb matches a fmt verb, optionally with an explicit argument index
+var formatVerb = regexp.MustCompile(`%(\[\d+\])?[vdsq]`)
+
+func TestMessageCatalogsHaveTheSameKeys(t *testing.T) {
+	for locale, catalog := range messages {
+		for other, otherCatalog := range messages {
+			for key := range catalog {
+				_, ok := otherCatalog[key]
+				assert.True(t, ok, "%q of %s is missing in %s", key, locale, other)
+			}
+		}
+	}
+}
+
+func TestMessageTranslationsTakeTheSameArguments(t *testing.T) {
+	for key, text := range messages[DefaultLocale] {
+		want := len(formatVerb.FindAllString(text, -1))
+		for locale, catalog := range messages {
+			if translated, ok := catalog[key]; ok {
+				assert.Equal(t, want, len(formatVerb.FindAllString(translated, -1)), "%q in %s", key, locale)
+			}
+		}
+	}
+}
+
+func TestTranslationWithReorderedArguments(t *testing.T) {
+	assert.Equal(t, "⏰ Через 15 мин начнётся «Стендап»", tr(LocaleRu, "reminder.message", 15, "Стендап", ""))
+	assert.Equal(t, "⏰ “Standup” starts in 15 min", tr(LocaleEn, "reminder.message", 15, "Standup", ""))
+}
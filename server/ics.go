@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+)
+
+// iCalendar export settings
+const (
+	icsContentType         = "text/calendar; charset=utf-8"
+	icsDateTimeLayout      = "20060102T150405Z"
+	icsLineLimit           = 75 // Octets per content line before folding (RFC 5545, 3.1)
+	icsDefaultAlarmMinutes = 15 // Alarm offset when ReminderMinutes is empty
+)
+
+var icsTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// buildMeetingICS renders the meeting as an RFC 5545 calendar with one VEVENT, its texts in the locale
+func (p *Plugin) buildMeetingICS(meeting *Meeting, organizer *model.User, participants []*model.User, locale string) []byte {
+	title := localizedMeetingTitle(meeting, locale)
+	now := time.Now().UTC().Format(icsDateTimeLayout)
+
+	method, status := "PUBLISH", "CONFIRMED"
+	if meeting.IsCancelled() {
+		method, status = "CANCEL", "CANCELLED"
+	}
+
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Skyeng//Kontur Meeting Plugin//RU",
+		"CALSCALE:GREGORIAN",
+		"METHOD:" + method,
+		"BEGIN:VEVENT",
+		fmt.Sprintf("UID:%s@%s", meeting.ID, PluginID),
+		// Calendars replace an imported event only when the sequence grows
+		fmt.Sprintf("SEQUENCE:%d", meeting.Sequence),
+		"DTSTAMP:" + now,
+		"DTSTART:" + meeting.StartTime().UTC().Format(icsDateTimeLayout),
+		"DTEND:" + meeting.EndTime().UTC().Format(icsDateTimeLayout),
+		"SUMMARY:" + escapeICSText(title),
+		"STATUS:" + status,
+	}
+	if meeting.RoomURL != "" {
+		lines = append(lines,
+			"LOCATION:"+escapeICSText(meeting.RoomURL),
+			"URL:"+meeting.RoomURL,
+			"DESCRIPTION:"+escapeICSText(tr(locale, "ics.description", meeting.RoomURL)),
+		)
+	}
+	if organizer != nil && organizer.Email != "" {
+		lines = append(lines, fmt.Sprintf("ORGANIZER;CN=%s:mailto:%s", icsParamValue(organizer.GetDisplayName(model.ShowFullName)), organizer.Email))
+	}
+	for _, user := range participants {
+		if user.Email == "" || (organizer != nil && user.Id == organizer.Id) {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("ATTENDEE;CN=%s;ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION:mailto:%s",
+			icsParamValue(user.GetDisplayName(model.ShowFullName)), user.Email))
+	}
+
+	if !meeting.IsCancelled() {
+		alarms := parseReminderOffsets(p.getConfiguration().ReminderMinutes)
+		if len(alarms) == 0 {
+			alarms = []int{icsDefaultAlarmMinutes}
+		}
+		for _, minutes := range alarms {
+			lines = append(lines,
+				"BEGIN:VALARM",
+				"ACTION:DISPLAY",
+				fmt.Sprintf("TRIGGER:-PT%dM", minutes),
+				"DESCRIPTION:"+escapeICSText(tr(locale, "ics.alarm", title, minutes)),
+				"END:VALARM",
+			)
+		}
+	}
+
+	lines = append(lines, "END:VEVENT", "END:VCALENDAR")
+
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(foldICSLine(line))
+		b.WriteString("\r\n")
+	}
+	return []byte(b.String())
+}
+
+// icsFilename returns the file name of the meeting calendar file
+func icsFilename(meeting *Meeting) string {
+	return fmt.Sprintf("meeting-%s.ics", meeting.StartTime().In(loadLocation(meeting.Timezone)).Format("2006-01-02-1504"))
+}
+
+// meetingICSURL returns the link to the meeting calendar file
+func (p *Plugin) meetingICSURL(meeting *Meeting) string {
+	return fmt.Sprintf("%s/plugins/%s/api/meetings/%s/ics", p.siteURL(), PluginID, meeting.ID)
+}
+
+// handleMeetingICS handles GET /api/meetings/{id}/ics: the meeting as an .ics file
+func (p *Plugin) handleMeetingICS(w http.ResponseWriter, r *http.Request, userID, locale string, meeting *Meeting) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, RequestFieldGeneral, tr(locale, "meeting.method_get"))
+		return
+	}
+	if _, appErr := p.API.GetChannelMember(meeting.ChannelID, userID); appErr != nil {
+		writeErrorResponse(w, http.StatusForbidden, RequestFieldChannelID, tr(locale, "access.not_meeting_channel_member"))
+		return
+	}
+
+	organizer, err := p.getUserSafely(meeting.OrganizerID)
+	if err != nil {
+		p.API.LogWarn("[Kontur] Failed to get meeting organizer", "meeting_id", meeting.ID, "error", err.Error())
+	}
+
+	w.Header().Set("Content-Type", icsContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", icsFilename(meeting)))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(p.buildMeetingICS(meeting, organizer, p.getMeetingParticipants(meeting), locale)); err != nil {
+		p.API.LogError("[Kontur] Failed to write ics response", "error", err.Error())
+	}
+}
+
+// uploadMeetingICS uploads the calendar file for the announcement post, in the organizer's locale like the post
+func (p *Plugin) uploadMeetingICS(meeting *Meeting, organizer *model.User, participants []*model.User) (string, error) {
+	fileInfo, appErr := p.API.UploadFile(p.buildMeetingICS(meeting, organizer, participants, meeting.Locale), meeting.ChannelID, icsFilename(meeting))
+	if appErr != nil {
+		return "", fmt.Errorf("failed to upload ics: %s", appErr.Error())
+	}
+	return fileInfo.Id, nil
+}
+
+// escapeICSText escapes a TEXT value (RFC 5545, 3.3.11)
+func escapeICSText(value string) string {
+	return icsTextEscaper.Replace(value)
+}
+
+// icsParamValue quotes a parameter value such as CN, which may contain commas or colons
+func icsParamValue(value string) string {
+	return `"` + strings.ReplaceAll(value, `"`, "'") + `"`
+}
+
+// foldICSLine splits a content line into 75-octet chunks without breaking UTF-8 characters
+func foldICSLine(line string) string {
+	if len(line) <= icsLineLimit {
+		return line
+	}
+
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > icsLineLimit {
+			// Continuation lines start with a space, which counts towards the limit
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// icsLines unfolds a calendar and splits it into content lines
+func icsLines(t *testing.T, ics []byte) []string {
+	text := string(ics)
+	require.True(t, strings.HasSuffix(text, "\r\n"))
+	return strings.Split(strings.TrimSuffix(strings.ReplaceAll(text, "\r\n ", ""), "\r\n"), "\r\n")
+}
+
+func newICSMeeting() *Meeting {
+	return &Meeting{
+		ID:              "meeting1",
+		Status:          MeetingStatusScheduled,
+		Title:           "Планёрка; итоги, планы",
+		ChannelID:       "channel",
+		OrganizerID:     "organizer",
+		ParticipantIDs:  []string{"organizer", "anna", "bot"},
+		StartAt:         model.GetMillisForTime(time.Date(2025, 1, 20, 7, 0, 0, 0, time.UTC)),
+		DurationMinutes: 45,
+		Timezone:        DefaultTimezone,
+		RoomURL:         "https://meet.example.com/room",
+		Sequence:        2,
+	}
+}
+
+func TestFoldICSLine(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		line string
+	}{
+		{"short", "SUMMARY:Standup"},
+		{"exactly at the limit", "DESCRIPTION:" + strings.Repeat("a", icsLineLimit-len("DESCRIPTION:"))},
+		{"ascii", "DESCRIPTION:" + strings.Repeat("abcdefghij", 20)},
+		{"multibyte", "SUMMARY:" + strings.Repeat("Планёрка ", 20)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			folded := foldICSLine(tc.line)
+
+			assert.Equal(t, tc.line, strings.ReplaceAll(folded, "\r\n ", ""))
+			parts := strings.Split(folded, "\r\n")
+			assert.Equal(t, len(tc.line) > icsLineLimit, len(parts) > 1)
+			for i, part := range parts {
+				assert.LessOrEqual(t, len(part), icsLineLimit)
+				assert.True(t, strings.ToValidUTF8(part, "") == part, "part %d breaks a character", i)
+				if i > 0 {
+					assert.True(t, strings.HasPrefix(part, " "))
+				}
+			}
+		})
+	}
+}
+
+func TestEscapeICSText(t *testing.T) {
+	for _, tc := range []struct {
+		in, want string
+	}{
+		{"Standup", "Standup"},
+		{"a;b,c", `a\;b\,c`},
+		{`C:\temp`, `C:\\temp`},
+		{"line1\nline2\r\nline3", `line1\nline2\nline3`},
+		{`\;`, `\\\;`},
+	} {
+		assert.Equal(t, tc.want, escapeICSText(tc.in), tc.in)
+	}
+}
+
+func TestBuildMeetingICS(t *testing.T) {
+	p, _ := newTestPlugin(t)
+	organizer := &model.User{Id: "organizer", Email: "olga@example.com", FirstName: "Ольга", LastName: "Петрова"}
+	participants := []*model.User{
+		organizer,
+		{Id: "anna", Email: "anna@example.com", Username: "anna", FirstName: "Anna", LastName: "Smith, PM"},
+		{Id: "bot", Username: "bot"},
+	}
+
+	lines := icsLines(t, p.buildMeetingICS(newICSMeeting(), organizer, participants, LocaleEn))
+
+	assert.Equal(t, "BEGIN:VCALENDAR", lines[0])
+	assert.Equal(t, "END:VCALENDAR", lines[len(lines)-1])
+	assert.Contains(t, lines, "METHOD:PUBLISH")
+	assert.Contains(t, lines, "UID:meeting1@"+PluginID)
+	assert.Contains(t, lines, "SEQUENCE:2")
+	assert.Contains(t, lines, "STATUS:CONFIRMED")
+	assert.Contains(t, lines, "DTSTART:20250120T070000Z")
+	assert.Contains(t, lines, "DTEND:20250120T074500Z")
+	assert.Contains(t, lines, `SUMMARY:Планёрка\; итоги\, планы`)
+	assert.Contains(t, lines, "DESCRIPTION:"+escapeICSText(tr(LocaleEn, "ics.description", "https://meet.example.com/room")))
+
+	// The organizer isn't repeated as an attendee and users without an email are skipped
+	assert.Contains(t, lines, `ORGANIZER;CN="Ольга Петрова":mailto:olga@example.com`)
+	var attendees []string
+	for _, line := range lines {
+		if strings.HasPrefix(line, "ATTENDEE") {
+			attendees = append(attendees, line)
+		}
+	}
+	assert.Equal(t, []string{`ATTENDEE;CN="Anna Smith, PM";ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION:mailto:anna@example.com`}, attendees)
+
+	// One alarm per reminder offset
+	var triggers []string
+	for _, line := range lines {
+		if strings.HasPrefix(line, "TRIGGER:") {
+			triggers = append(triggers, line)
+		}
+	}
+	assert.Equal(t, []string{"TRIGGER:-PT15M", "TRIGGER:-PT1M"}, triggers)
+	assert.Equal(t, 2, strings.Count(strings.Join(lines, "\n"), "BEGIN:VALARM"))
+	assert.Equal(t, 2, strings.Count(strings.Join(lines, "\n"), "END:VALARM"))
+}
+
+func TestBuildMeetingICSDefaultAlarm(t *testing.T) {
+	p, _ := newTestPlugin(t)
+	p.configuration.ReminderMinutes = ""
+
+	lines := icsLines(t, p.buildMeetingICS(newICSMeeting(), nil, nil, LocaleRu))
+
+	assert.Contains(t, lines, "TRIGGER:-PT15M")
+	assert.NotContains(t, strings.Join(lines, "\n"), "ORGANIZER")
+}
+
+func TestBuildMeetingICSCancelled(t *testing.T) {
+	p, _ := newTestPlugin(t)
+	meeting := newICSMeeting()
+	meeting.Status = MeetingStatusCancelled
+	meeting.Sequence = 3
+
+	lines := icsLines(t, p.buildMeetingICS(meeting, nil, nil, LocaleEn))
+
+	assert.Contains(t, lines, "METHOD:CANCEL")
+	assert.Contains(t, lines, "STATUS:CANCELLED")
+	assert.Contains(t, lines, "SEQUENCE:3")
+	assert.NotContains(t, lines, "BEGIN:VALARM")
+}
+
+func TestMeetingICSRequiresChannelMembership(t *testing.T) {
+	p, api := newTestPlugin(t)
+	require.NoError(t, p.updateMeeting(newICSMeeting()))
+	api.On("GetUser", "outsider").Return(&model.User{Id: "outsider", Locale: "en"}, nil)
+	api.On("GetChannelMember", "channel", "outsider").Return(nil, model.NewAppError("GetChannelMember", "not_found", nil, "", http.StatusNotFound))
+
+	w := serve(p, http.MethodGet, "/api/meetings/meeting1/ics", "outsider", "")
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), tr(LocaleEn, "access.not_meeting_channel_member"))
+	assert.NotContains(t, w.Body.String(), "BEGIN:VCALENDAR")
+}
+
+func TestMeetingICSForChannelMember(t *testing.T) {
+	p, api := newTestPlugin(t)
+	require.NoError(t, p.updateMeeting(newICSMeeting()))
+	api.On("GetUser", "anna").Return(&model.User{Id: "anna", Username: "anna", Email: "anna@example.com", Locale: "en"}, nil)
+	api.On("GetUser", "organizer").Return(&model.User{Id: "organizer", Email: "olga@example.com"}, nil)
+	api.On("GetUser", "bot").Return(&model.User{Id: "bot"}, nil)
+	api.On("GetChannelMember", "channel", "anna").Return(&model.ChannelMember{ChannelId: "channel", UserId: "anna"}, nil)
+
+	w := serve(p, http.MethodGet, "/api/meetings/meeting1/ics", "anna", "")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, icsContentType, w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "meeting-2025-01-20-1000.ics")
+	lines := icsLines(t, w.Body.Bytes())
+	assert.Contains(t, lines, "SEQUENCE:2")
+	assert.Contains(t, lines, "ATTENDEE;CN=\"anna\";ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION:mailto:anna@example.com")
+}
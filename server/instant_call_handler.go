@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+)
+
+// InstantCallRequest represents the instant call request
+type InstantCallRequest struct {
+	ChannelID string `json:"channel_id"`
+	TeamID    string `json:"team_id"`
+	UserID    string `json:"user_id"`
+	RootID    string `json:"root_id"` // ID родительского сообщения для создания поста в треде
+}
+
+// handleInstantCall handles the instant call endpoint
+func (p *Plugin) handleInstantCall(w http.ResponseWriter, r *http.Request, userID string) {
+	// Messages follow the acting user's Mattermost language
+	var locale string
+
+	// Recover from panic
+	defer func() {
+		if rec := recover(); rec != nil {
+			if p != nil && p.API != nil {
+				p.API.LogError("[Kontur] Panic recovered", "panic", fmt.Sprintf("%v", rec))
+			}
+			if w.Header().Get("Content-Type") == "" {
+				writeErrorResponse(w, http.StatusInternalServerError, RequestFieldGeneral,
+					tr(locale, "request.internal_error", rec))
+			}
+		}
+	}()
+
+	// Check initialization
+	if p == nil || p.API == nil {
+		http.Error(w, "Plugin not initialized", http.StatusInternalServerError)
+		return
+	}
+	locale = p.userLocale(userID)
+
+	p.API.LogDebug("[Kontur] instant-call called")
+
+	// Only allow POST requests
+	if r.Method != http.MethodPost {
+		p.API.LogWarn("[Kontur] Method not allowed", "method", r.Method)
+		writeErrorResponse(w, http.StatusMethodNotAllowed, RequestFieldGeneral, tr(locale, "request.method_not_allowed"))
+		return
+	}
+
+	// Step 1: Parse request
+	var callReq InstantCallRequest
+	if err := json.NewDecoder(r.Body).Decode(&callReq); err != nil {
+		p.API.LogError("[Kontur] Failed to parse JSON", "error", err.Error())
+		writeErrorResponse(w, http.StatusBadRequest, RequestFieldGeneral, tr(locale, "request.invalid_json", err.Error()))
+		return
+	}
+
+	// The acting user is the authenticated one; body user_id may only repeat it
+	actingUserID, err := resolveActingUserID(userID, callReq.UserID)
+	if err != nil {
+		p.API.LogWarn("[Kontur] Instant call user mismatch", "error", err.Error())
+		writeErrorResponse(w, http.StatusForbidden, RequestFieldUserID, tr(locale, "request.user_mismatch"))
+		return
+	}
+	callReq.UserID = actingUserID
+
+	p.API.LogInfo("[Kontur] Instant call request received",
+		RequestFieldChannelID, callReq.ChannelID,
+		RequestFieldUserID, callReq.UserID)
+
+	if callReq.ChannelID == "" {
+		writeErrorResponse(w, http.StatusBadRequest, RequestFieldChannelID, tr(locale, "validation.channel_required"))
+		return
+	}
+
+	// Instant call goes through the same pipeline as a scheduled meeting
+	req := &ScheduleRequest{
+		ChannelID:     callReq.ChannelID,
+		TeamID:        callReq.TeamID,
+		UserID:        callReq.UserID,
+		RootID:        callReq.RootID,
+		OperationType: OperationInstantCall,
+		locale:        locale,
+	}
+
+	// Step 2: Create the meeting through the shared pipeline
+	meeting, reqErr := p.startInstantCall(req)
+	if reqErr != nil {
+		writeErrorResponse(w, reqErr.StatusCode, reqErr.Field, reqErr.Message)
+		return
+	}
+
+	// Step 3: Return success response
+	p.API.LogInfo("[Kontur] Instant call created successfully", "room_url", meeting.RoomURL)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	message := tr(locale, "schedule.success")
+	if meeting.IsPending() {
+		message = tr(locale, "schedule.pending")
+	}
+	response := map[string]interface{}{
+		"status":     "success",
+		"message":    message,
+		"room_url":   meeting.RoomURL,
+		"meeting_id": meeting.ID,
+		"pending":    meeting.IsPending(),
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		p.API.LogError("[Kontur] Failed to encode success response", "error", err.Error())
+	}
+}
+
+// startInstantCall creates an instant meeting in the request channel and announces it
+func (p *Plugin) startInstantCall(req *ScheduleRequest) (*Meeting, *RequestError) {
+	req.OperationType = OperationInstantCall
+
+	// Get user and channel
+	currentUser, channel, reqErr := p.getUserAndChannelForRequest(req)
+	if reqErr != nil {
+		return nil, reqErr
+	}
+
+	if req.locale == "" {
+		req.locale = normalizeLocale(currentUser.Locale)
+	}
+
+	// Check that the user may post in the channel
+	if err := p.checkChannelAccess(currentUser.Id, channel, req.locale); err != nil {
+		return nil, &RequestError{StatusCode: http.StatusForbidden, Field: RequestFieldChannelID, Message: err.Error()}
+	}
+
+	return p.createMeeting(req, currentUser, channel, []*model.User{}, time.Now())
+}
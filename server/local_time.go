@@ -0,0 +1,70 @@
+package main
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+)
+
+// Local time notice modes, selected by the LocalTimeNotice setting
+const (
+	LocalTimeNoticeOff       = "off"
+	LocalTimeNoticeEphemeral = "ephemeral" // Visible only to the recipient, in the meeting channel
+	LocalTimeNoticeDM        = "dm"        // Direct message from the bot
+)
+
+// sendLocalTimeNotices tells every participant whose zone differs from the meeting zone
+// when the meeting starts in their own profile timezone and locale
+func (p *Plugin) sendLocalTimeNotices(meeting *Meeting, rescheduled bool) {
+	mode := p.getConfiguration().LocalTimeNotice
+	if mode == "" || mode == LocalTimeNoticeOff || meeting.IsCancelled() || meeting.OperationType == OperationInstantCall {
+		return
+	}
+
+	_, meetingOffset := meeting.StartTime().In(loadLocation(meeting.Timezone)).Zone()
+	for _, userID := range meeting.UserIDs() {
+		user, err := p.getUserSafely(userID)
+		if err != nil {
+			continue
+		}
+		loc := userLocation(user)
+		// People in the meeting zone already read the right time in the post
+		if _, offset := meeting.StartTime().In(loc).Zone(); offset == meetingOffset {
+			continue
+		}
+
+		message := localTimeNoticeText(meeting, loc, normalizeLocale(user.Locale), rescheduled)
+		if err := p.deliverLocalTimeNotice(mode, meeting, userID, message); err != nil {
+			p.API.LogWarn("[Kontur] Failed to send local time notice", "meeting_id", meeting.ID, "user_id", userID, "error", err.Error())
+		}
+	}
+}
+
+// deliverLocalTimeNotice sends the notice as an ephemeral post in the meeting channel or as a DM
+func (p *Plugin) deliverLocalTimeNotice(mode string, meeting *Meeting, userID, message string) error {
+	if mode == LocalTimeNoticeDM {
+		return p.sendDirectMessage(userID, message)
+	}
+
+	post := &model.Post{
+		ChannelId: meeting.ChannelID,
+		UserId:    p.botUserID,
+		RootId:    meeting.RootID,
+		Message:   message,
+	}
+	post.AddProp(PostPropMeetingID, meeting.ID)
+	p.API.SendEphemeralPost(userID, post)
+	return nil
+}
+
+// localTimeNoticeText renders the meeting start in the recipient's zone and locale
+func localTimeNoticeText(meeting *Meeting, loc *time.Location, locale string, rescheduled bool) string {
+	start := meeting.StartTime()
+	when := start.In(loc).Format(tr(locale, "datetime.layout"))
+
+	key := "notice.starts"
+	if rescheduled {
+		key = "notice.rescheduled"
+	}
+	return tr(locale, key, localizedMeetingTitle(meeting, locale), when, zoneLabel(start, loc, locale))
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+)
+
+// Meeting statuses
+const (
+	MeetingStatusPending   = "pending" // Waiting for the provider callback with the room
+	MeetingStatusScheduled = "scheduled"
+	MeetingStatusCancelled = "cancelled"
+)
+
+// Live states of the call reported by provider events
+const (
+	LiveStatusStarted = "started"
+	LiveStatusEnded   = "ended"
+)
+
+// Meeting is a meeting created through the plugin, persisted in the KV store
+type Meeting struct {
+	ID               string   `json:"id"`
+	OperationType    string   `json:"operation_type"`
+	Status           string   `json:"status"`
+	Title            string   `json:"title"`
+	ChannelID        string   `json:"channel_id"`
+	TeamID           string   `json:"team_id"`
+	RootID           string   `json:"root_id"`
+	PostID           string   `json:"post_id"`
+	OrganizerID      string   `json:"organizer_id"`
+	ParticipantIDs   []string `json:"participant_ids"`
+	StartAt          int64    `json:"start_at"` // Unix time in milliseconds
+	DurationMinutes  int      `json:"duration_minutes"`
+	Timezone         string   `json:"timezone"`
+	RoomURL          string   `json:"room_url"`
+	RoomID           string   `json:"room_id"`
+	RemindersSent    []int    `json:"reminders_sent,omitempty"` // Offsets in minutes already delivered
+	FailureReason    string   `json:"failure_reason,omitempty"` // Why the provider failed to create a cancelled meeting
+	LiveStatus       string   `json:"live_status,omitempty"`    // Call state from provider events
+	StartedAt        int64    `json:"started_at,omitempty"`
+	EndedAt          int64    `json:"ended_at,omitempty"`
+	ParticipantCount int      `json:"participant_count,omitempty"` // People currently in the call
+	RecordingURL     string   `json:"recording_url,omitempty"`
+	TranscriptURL    string   `json:"transcript_url,omitempty"`
+	TranscriptReady  bool     `json:"transcript_ready,omitempty"`
+	SeriesID         string   `json:"series_id,omitempty"`  // Recurring series the meeting belongs to
+	Recurrence       string   `json:"recurrence,omitempty"` // RRULE of the series
+	Locale           string   `json:"locale,omitempty"`     // Organizer's locale the post is rendered in
+	Sequence         int      `json:"sequence,omitempty"`   // Revision of the calendar event, bumped on edits and cancellation
+	CreateAt         int64    `json:"create_at"`
+	UpdateAt         int64    `json:"update_at"`
+}
+
+// newMeeting builds a meeting record from a processed schedule request
+func newMeeting(id string, req *ScheduleRequest, organizer *model.User, channel *model.Channel, participants []*model.User, scheduledAt time.Time) *Meeting {
+	operationType := req.OperationType
+	if operationType == "" {
+		operationType = OperationScheduledMeeting
+	}
+
+	title := ""
+	if req.Title != nil {
+		title = *req.Title
+	}
+
+	timezone := req.Timezone
+	if timezone == "" {
+		timezone = DefaultTimezone
+	}
+
+	participantIDs := make([]string, 0, len(participants))
+	for _, user := range participants {
+		participantIDs = append(participantIDs, user.Id)
+	}
+
+	seriesID, recurrence := "", ""
+	if req.series != nil {
+		seriesID, recurrence = req.series.ID, req.series.RRule
+	}
+
+	now := model.GetMillis()
+	return &Meeting{
+		ID:              id,
+		OperationType:   operationType,
+		Status:          MeetingStatusScheduled,
+		Title:           title,
+		ChannelID:       channel.Id,
+		TeamID:          channel.TeamId,
+		RootID:          req.RootID,
+		OrganizerID:     organizer.Id,
+		ParticipantIDs:  participantIDs,
+		StartAt:         model.GetMillisForTime(scheduledAt),
+		DurationMinutes: req.DurationMinutes,
+		Timezone:        timezone,
+		SeriesID:        seriesID,
+		Recurrence:      recurrence,
+		Locale:          req.locale,
+		CreateAt:        now,
+		UpdateAt:        now,
+	}
+}
+
+// StartTime returns the meeting start time
+func (m *Meeting) StartTime() time.Time {
+	return model.GetTimeForMillis(m.StartAt)
+}
+
+// EndTime returns the meeting end time
+func (m *Meeting) EndTime() time.Time {
+	return m.StartTime().Add(time.Duration(m.DurationMinutes) * time.Minute)
+}
+
+// IsPending reports whether the room is still being created by the provider
+func (m *Meeting) IsPending() bool {
+	return m.Status == MeetingStatusPending
+}
+
+// IsCancelled reports whether the meeting was cancelled
+func (m *Meeting) IsCancelled() bool {
+	return m.Status == MeetingStatusCancelled
+}
+
+// IsLive reports whether the call is in progress
+func (m *Meeting) IsLive() bool {
+	return m.LiveStatus == LiveStatusStarted
+}
+
+// HasEnded reports whether the provider reported the call as finished
+func (m *Meeting) HasEnded() bool {
+	return m.LiveStatus == LiveStatusEnded
+}
+
+// ActualDuration returns how long the call lasted according to provider events
+func (m *Meeting) ActualDuration() time.Duration {
+	if m.StartedAt == 0 || m.EndedAt < m.StartedAt {
+		return 0
+	}
+	return time.Duration(m.EndedAt-m.StartedAt) * time.Millisecond
+}
+
+// ReminderSent reports whether the reminder with the given offset was already delivered
+func (m *Meeting) ReminderSent(minutes int) bool {
+	for _, sent := range m.RemindersSent {
+		if sent == minutes {
+			return true
+		}
+	}
+	return false
+}
+
+// UserIDs returns the organizer and all participants without duplicates
+func (m *Meeting) UserIDs() []string {
+	userIDs := []string{m.OrganizerID}
+	for _, id := range m.ParticipantIDs {
+		if id != m.OrganizerID {
+			userIDs = append(userIDs, id)
+		}
+	}
+	return userIDs
+}
+
+// threadRootID returns the thread of the meeting announcement, where follow-ups are replied
+func (m *Meeting) threadRootID() string {
+	// An announcement created inside a thread can't be a root itself
+	if m.RootID != "" {
+		return m.RootID
+	}
+	return m.PostID
+}
+
+// localizedMeetingTitle returns the meeting title or the generic name in the locale
+func localizedMeetingTitle(meeting *Meeting, locale string) string {
+	if meeting.Title == "" {
+		return tr(locale, "meeting.default_title")
+	}
+	return meeting.Title
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+)
+
+// Post action names, each handled by /api/actions/<name>
+const (
+	ActionJoin       = "join"
+	ActionCalendar   = "calendar"
+	ActionCancel     = "cancel"
+	ActionReschedule = "reschedule"
+)
+
+// Post props holding the meeting ID and the machine-readable meeting time
+const (
+	PostPropMeetingID = "kontur_meeting_id"
+	PostPropStartAt   = "kontur_meeting_start_at" // Unix time in milliseconds
+	PostPropEndAt     = "kontur_meeting_end_at"
+	PostPropTimezone  = "kontur_meeting_timezone" // IANA zone the meeting was scheduled in
+)
+
+// Attachment colors per meeting state
+const (
+	colorPending   = "#f5a623"
+	colorScheduled = "#1a73e8"
+	colorLive      = "#d24b4e"
+	colorEnded     = "#3db887"
+	colorCancelled = "#8a8a8a"
+)
+
+// renderMeetingPost fills the post message and attachment from the current meeting state
+func (p *Plugin) renderMeetingPost(post *model.Post, meeting *Meeting, organizer *model.User, participants []*model.User) {
+	// Format participants list
+	participantsList := ""
+	for i, user := range participants {
+		if i > 0 {
+			participantsList += ", "
+		}
+		participantsList += "@" + user.Username
+	}
+
+	// Posts are rendered in the organizer's locale, so they look the same after anyone's update
+	locale := meeting.Locale
+	title := localizedMeetingTitle(meeting, locale)
+	scheduledAtFormatted := p.formatMeetingTime(meeting.StartTime(), loadLocation(meeting.Timezone), locale)
+
+	// The message keeps the @mentions so participants get notified
+	var message string
+	switch {
+	case meeting.IsCancelled() && meeting.FailureReason != "":
+		message = tr(locale, "post.failed", title, scheduledAtFormatted)
+	case meeting.IsCancelled():
+		message = tr(locale, "post.cancelled", title, scheduledAtFormatted)
+	case meeting.IsPending():
+		message = tr(locale, "post.pending", organizer.Username, scheduledAtFormatted)
+	default:
+		message = tr(locale, "post.scheduled", organizer.Username, scheduledAtFormatted)
+	}
+	message += "\n\n" + tr(locale, "post.participants", participantsList)
+	post.Message = message
+
+	attachment := &model.SlackAttachment{
+		Fallback: message,
+		Title:    title,
+		Fields: []*model.SlackAttachmentField{
+			{Title: tr(locale, "post.field.start"), Value: scheduledAtFormatted, Short: true},
+			{Title: tr(locale, "post.field.duration"), Value: tr(locale, "post.duration", meeting.DurationMinutes), Short: true},
+			{Title: tr(locale, "post.field.organizer"), Value: "@" + organizer.Username, Short: true},
+		},
+	}
+	if meeting.Recurrence != "" {
+		if rule, err := parseRRule(meeting.Recurrence, loadLocation(meeting.Timezone), locale); err == nil {
+			attachment.Fields = append(attachment.Fields, &model.SlackAttachmentField{Title: tr(locale, "post.field.recurrence"), Value: rule.Describe(locale), Short: true})
+		}
+	}
+
+	switch {
+	case meeting.IsCancelled() && meeting.FailureReason != "":
+		attachment.Color = colorCancelled
+		attachment.Text = meeting.FailureReason
+	case meeting.IsCancelled():
+		attachment.Color = colorCancelled
+		attachment.Text = tr(locale, "post.cancelled_text")
+	case meeting.IsPending():
+		attachment.Color = colorPending
+		attachment.Text = tr(locale, "post.pending_text")
+	case meeting.HasEnded():
+		attachment.Color = colorEnded
+		attachment.Fields = append(attachment.Fields, &model.SlackAttachmentField{Title: tr(locale, "post.field.status"), Value: liveStatusText(meeting, locale), Short: true})
+	case meeting.IsLive():
+		attachment.Color = colorLive
+		attachment.TitleLink = meeting.RoomURL
+		attachment.Text = tr(locale, "post.join_link", meeting.RoomURL)
+		attachment.Fields = append(attachment.Fields, &model.SlackAttachmentField{Title: tr(locale, "post.field.status"), Value: liveStatusText(meeting, locale), Short: true})
+		attachment.Actions = []*model.PostAction{
+			meetingAction(meeting, ActionJoin, tr(locale, "post.button.join"), "primary"),
+		}
+	default:
+		attachment.Color = colorScheduled
+		attachment.TitleLink = meeting.RoomURL
+		attachment.Text = tr(locale, "post.join_link", meeting.RoomURL)
+		attachment.Actions = []*model.PostAction{
+			meetingAction(meeting, ActionJoin, tr(locale, "post.button.join"), "primary"),
+			meetingAction(meeting, ActionCalendar, tr(locale, "post.button.calendar"), "default"),
+			meetingAction(meeting, ActionReschedule, tr(locale, "post.button.reschedule"), "default"),
+			meetingAction(meeting, ActionCancel, tr(locale, "post.button.cancel"), "danger"),
+		}
+	}
+	if meeting.RecordingURL != "" {
+		attachment.Text = strings.TrimSpace(attachment.Text + "\n" + tr(locale, "post.recording_link", meeting.RecordingURL))
+	}
+
+	post.AddProp(PostPropMeetingID, meeting.ID)
+	post.AddProp(PostPropStartAt, meeting.StartAt)
+	post.AddProp(PostPropEndAt, model.GetMillisForTime(meeting.EndTime()))
+	post.AddProp(PostPropTimezone, meeting.Timezone)
+	model.ParseSlackAttachment(post, []*model.SlackAttachment{attachment})
+}
+
+// renderInstantCallPost fills the plain instant call message from the current meeting state
+func (p *Plugin) renderInstantCallPost(post *model.Post, meeting *Meeting, organizer *model.User) {
+	// Bot posts carry the organizer and mention them, own posts speak in the first person
+	byBot := post.GetProp("organizer_id") != nil
+	locale := meeting.Locale
+
+	switch {
+	case meeting.IsCancelled() && meeting.FailureReason != "":
+		post.Message = tr(locale, "instant.failed", meeting.FailureReason)
+	case meeting.IsCancelled():
+		post.Message = tr(locale, "instant.cancelled")
+	case meeting.IsPending() && byBot:
+		post.Message = tr(locale, "instant.pending_by", organizer.Username)
+	case meeting.IsPending():
+		post.Message = tr(locale, "instant.pending")
+	case byBot:
+		post.Message = tr(locale, "instant.created_by", organizer.Username, meeting.RoomURL)
+	default:
+		post.Message = tr(locale, "instant.created", meeting.RoomURL)
+	}
+	if status := liveStatusText(meeting, locale); status != "" && !meeting.IsCancelled() {
+		post.Message += "\n" + status
+	}
+	if meeting.RecordingURL != "" {
+		post.Message += "\n" + tr(locale, "post.recording_link", meeting.RecordingURL)
+	}
+	post.AddProp(PostPropMeetingID, meeting.ID)
+}
+
+// liveStatusText describes the call state reported by provider events, e.g. "🔴 Идёт сейчас, 4 участника"
+func liveStatusText(meeting *Meeting, locale string) string {
+	switch {
+	case meeting.IsLive():
+		if meeting.ParticipantCount == 0 {
+			return tr(locale, "live.started")
+		}
+		return tr(locale, "live.started_count", meeting.ParticipantCount,
+			trPlural(locale, "live.participants", meeting.ParticipantCount))
+	case meeting.HasEnded():
+		minutes := int(meeting.ActualDuration().Round(time.Minute).Minutes())
+		if minutes == 0 {
+			return tr(locale, "live.ended")
+		}
+		return tr(locale, "live.ended_duration", minutes)
+	default:
+		return ""
+	}
+}
+
+// meetingAction builds a post button handled by the plugin
+func meetingAction(meeting *Meeting, name, label, style string) *model.PostAction {
+	return &model.PostAction{
+		Id:    name,
+		Type:  model.PostActionTypeButton,
+		Name:  label,
+		Style: style,
+		Integration: &model.PostActionIntegration{
+			URL: fmt.Sprintf("/plugins/%s/api/actions/%s", PluginID, name),
+			Context: map[string]interface{}{
+				"meeting_id": meeting.ID,
+			},
+		},
+	}
+}
+
+// updateMeetingPost re-renders the meeting announcement in place
+func (p *Plugin) updateMeetingPost(meeting *Meeting) error {
+	if meeting.PostID == "" {
+		return nil
+	}
+
+	post, appErr := p.API.GetPost(meeting.PostID)
+	if appErr != nil {
+		return fmt.Errorf("failed to get post %s: %s", meeting.PostID, appErr.Error())
+	}
+
+	organizer, err := p.getUserSafely(meeting.OrganizerID)
+	if err != nil {
+		return err
+	}
+	participants := p.getMeetingParticipants(meeting)
+
+	if meeting.OperationType == OperationInstantCall {
+		p.renderInstantCallPost(post, meeting, organizer)
+	} else {
+		p.renderMeetingPost(post, meeting, organizer, participants)
+	}
+	if _, appErr := p.API.UpdatePost(post); appErr != nil {
+		return fmt.Errorf("failed to update post %s: %s", meeting.PostID, appErr.Error())
+	}
+
+	p.API.LogDebug("[Kontur] Meeting post updated", "meeting_id", meeting.ID, "post_id", meeting.PostID)
+	return nil
+}
+
+// getMeetingParticipants loads participant users, skipping ones that can't be found
+func (p *Plugin) getMeetingParticipants(meeting *Meeting) []*model.User {
+	participants := make([]*model.User, 0, len(meeting.ParticipantIDs))
+	for _, userID := range meeting.ParticipantIDs {
+		user, err := p.getUserSafely(userID)
+		if err != nil {
+			continue
+		}
+		participants = append(participants, user)
+	}
+	return participants
+}
+
+// googleCalendarURL builds an "add event" link for Google Calendar
+func googleCalendarURL(meeting *Meeting, locale string) string {
+	const layout = "20060102T150405Z"
+
+	title := localizedMeetingTitle(meeting, locale)
+
+	query := url.Values{}
+	query.Set("action", "TEMPLATE")
+	query.Set("text", title)
+	query.Set("dates", strings.Join([]string{
+		meeting.StartTime().UTC().Format(layout),
+		meeting.EndTime().UTC().Format(layout),
+	}, "/"))
+	query.Set("details", meeting.RoomURL)
+	query.Set("location", meeting.RoomURL)
+
+	return "https://calendar.google.com/calendar/render?" + query.Encode()
+}
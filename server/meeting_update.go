@@ -0,0 +1,249 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+)
+
+// MeetingUpdateRequest is the body of PATCH /api/meetings/{id}; omitted fields stay unchanged
+type MeetingUpdateRequest struct {
+	StartAt         string    `json:"start_at"`
+	StartAtLocal    string    `json:"start_at_local"`
+	Timezone        string    `json:"timezone"`
+	DurationMinutes *int      `json:"duration_minutes"`
+	Title           *string   `json:"title"`
+	ParticipantIDs  *[]string `json:"participant_ids"`
+	Force           bool      `json:"force"` // Move the meeting even if participants are busy at the new time
+}
+
+// hasStart reports whether the update moves the meeting start
+func (u *MeetingUpdateRequest) hasStart() bool {
+	return u.StartAt != "" || u.StartAtLocal != ""
+}
+
+// scheduleRequest merges the update with the stored meeting so the schedule validations can be reused
+func (u *MeetingUpdateRequest) scheduleRequest(meeting *Meeting) *ScheduleRequest {
+	req := &ScheduleRequest{
+		ChannelID:       meeting.ChannelID,
+		TeamID:          meeting.TeamID,
+		UserID:          meeting.OrganizerID,
+		StartAt:         u.StartAt,
+		StartAtLocal:    u.StartAtLocal,
+		Timezone:        u.Timezone,
+		DurationMinutes: meeting.DurationMinutes,
+		Title:           u.Title,
+		ParticipantIDs:  meeting.ParticipantIDs,
+	}
+	if req.Timezone == "" {
+		req.Timezone = meeting.Timezone
+	}
+	if u.DurationMinutes != nil {
+		req.DurationMinutes = *u.DurationMinutes
+	}
+	if u.ParticipantIDs != nil {
+		req.ParticipantIDs = *u.ParticipantIDs
+	}
+	return req
+}
+
+// updateMeetingDetails validates the changes, propagates them to the provider and updates the stored meeting
+// and its announcement. Returns the changed fields as {"field": {"old": ..., "new": ...}}.
+func (p *Plugin) updateMeetingDetails(meeting *Meeting, update *MeetingUpdateRequest, userID string) (map[string]interface{}, *RequestError) {
+	locale := p.userLocale(userID)
+	if meeting.IsCancelled() {
+		return nil, &RequestError{StatusCode: http.StatusConflict, Field: RequestFieldGeneral, Message: tr(locale, "meeting.cancelled")}
+	}
+	if meeting.HasEnded() {
+		return nil, &RequestError{StatusCode: http.StatusConflict, Field: RequestFieldGeneral, Message: tr(locale, "meeting.ended")}
+	}
+	if meeting.IsPending() {
+		return nil, &RequestError{StatusCode: http.StatusConflict, Field: RequestFieldGeneral, Message: tr(locale, "meeting.pending")}
+	}
+
+	req := update.scheduleRequest(meeting)
+	req.locale = locale
+	updated := *meeting
+
+	if update.hasStart() {
+		startAt, err := p.parseDateTime(req)
+		if err != nil {
+			return nil, &RequestError{StatusCode: http.StatusBadRequest, Field: RequestFieldStartAtLocal, Message: err.Error()}
+		}
+		updated.StartAt = model.GetMillisForTime(startAt)
+		updated.Timezone = req.Timezone
+	}
+	updated.DurationMinutes = req.DurationMinutes
+	if update.Title != nil {
+		updated.Title = *update.Title
+	}
+
+	var participants []*model.User
+	if update.ParticipantIDs != nil {
+		channel, err := p.getChannelSafely(meeting.ChannelID)
+		if err != nil {
+			return nil, &RequestError{StatusCode: http.StatusNotFound, Field: RequestFieldChannelID,
+				Message: tr(locale, "schedule.channel_not_found", meeting.ChannelID)}
+		}
+		participants, err = p.resolveParticipants(req, channel)
+		if err != nil {
+			return nil, &RequestError{StatusCode: http.StatusBadRequest, Field: RequestFieldParticipantIDs, Message: err.Error()}
+		}
+		if err := p.checkParticipantsAccess(participants, channel, meeting.TeamID, locale); err != nil {
+			return nil, &RequestError{StatusCode: http.StatusForbidden, Field: RequestFieldParticipantIDs, Message: err.Error()}
+		}
+		updated.ParticipantIDs = make([]string, 0, len(participants))
+		for _, user := range participants {
+			updated.ParticipantIDs = append(updated.ParticipantIDs, user.Id)
+		}
+	}
+
+	changes := meetingChanges(meeting, &updated)
+	if len(changes) == 0 {
+		return changes, nil
+	}
+
+	// Moving a meeting onto busy time is refused like scheduling it there, unless the client confirmed it
+	_, moved := changes["start_time_utc"]
+	_, resized := changes["duration_minutes"]
+	_, invited := changes["participant_ids"]
+	if (moved || resized || invited) && !update.Force {
+		organizer, err := p.getUserSafely(updated.OrganizerID)
+		if err != nil {
+			return nil, &RequestError{StatusCode: http.StatusNotFound, Field: RequestFieldUserID,
+				Message: tr(locale, "schedule.user_not_found", updated.OrganizerID)}
+		}
+		if update.ParticipantIDs == nil {
+			participants = p.getMeetingParticipants(&updated)
+		}
+		if reqErr := p.checkConflicts(userID, meetingUsers(organizer, participants), updated.StartTime(), updated.EndTime(),
+			meeting.ID, loadLocation(updated.Timezone), locale); reqErr != nil {
+			return nil, reqErr
+		}
+	}
+
+	// Propagate the change first so the stored meeting never diverges from the provider
+	provider, reqErr := p.getMeetingProvider(locale)
+	if reqErr != nil {
+		return nil, reqErr
+	}
+	room, err := provider.UpdateMeeting(&ProviderRequest{Meeting: &updated, UserID: userID, Locale: locale, Changes: changes})
+	if err != nil {
+		return nil, providerRequestError(err, locale, "provider.update_failed")
+	}
+	// The provider may move the meeting to another room
+	if room != nil {
+		updated.RoomURL = room.URL
+		if room.ID != "" {
+			updated.RoomID = room.ID
+		}
+	}
+
+	// Only the edited fields are written over the fresh record, so concurrent updates such as
+	// provider events or sent reminders aren't lost
+	_, rescheduled := changes["start_time_utc"]
+	saved, err := p.modifyMeeting(meeting.ID, func(m *Meeting) bool {
+		if m.IsCancelled() {
+			return false
+		}
+		m.StartAt, m.Timezone, m.DurationMinutes = updated.StartAt, updated.Timezone, updated.DurationMinutes
+		m.Title, m.ParticipantIDs = updated.Title, updated.ParticipantIDs
+		m.RoomURL, m.RoomID = updated.RoomURL, updated.RoomID
+		if rescheduled {
+			// Reminders are due again relative to the new start
+			m.RemindersSent = nil
+		}
+		m.Sequence++
+		return true
+	})
+	if err != nil {
+		p.API.LogError("[Kontur] Failed to save updated meeting", "meeting_id", meeting.ID, "error", err.Error())
+		return nil, &RequestError{StatusCode: http.StatusInternalServerError, Field: RequestFieldGeneral,
+			Message: tr(locale, "meeting.update_save_failed")}
+	}
+	if saved.IsCancelled() {
+		return nil, &RequestError{StatusCode: http.StatusConflict, Field: RequestFieldGeneral, Message: tr(locale, "meeting.cancelled")}
+	}
+	p.updateMeetingIndexes(meeting, saved)
+	*meeting = *saved
+
+	if err := p.updateMeetingPost(meeting); err != nil {
+		p.API.LogWarn("[Kontur] Failed to update meeting post", "meeting_id", meeting.ID, "error", err.Error())
+	}
+	if rescheduled {
+		p.sendLocalTimeNotices(meeting, true)
+	}
+
+	p.API.LogInfo("[Kontur] Meeting updated", "meeting_id", meeting.ID, RequestFieldUserID, userID, "changed_fields", len(changes))
+	return changes, nil
+}
+
+// updateMeetingIndexes keeps the user and upcoming indexes in sync after an update
+func (p *Plugin) updateMeetingIndexes(old, updated *Meeting) {
+	oldUsers := map[string]bool{}
+	for _, userID := range old.UserIDs() {
+		oldUsers[userID] = true
+	}
+	for _, userID := range updated.UserIDs() {
+		if oldUsers[userID] {
+			delete(oldUsers, userID)
+			continue
+		}
+		if err := p.addToIndex(kvUserIndexPrefix+userID, updated.ID); err != nil {
+			p.API.LogError("[Kontur] Failed to index meeting participant", "meeting_id", updated.ID, "error", err.Error())
+		}
+	}
+	for userID := range oldUsers {
+		if err := p.removeFromIndex(kvUserIndexPrefix+userID, updated.ID); err != nil {
+			p.API.LogError("[Kontur] Failed to unindex meeting participant", "meeting_id", updated.ID, "error", err.Error())
+		}
+	}
+
+	// The reminder job may already have pruned the meeting
+	if updated.OperationType != OperationInstantCall && old.StartAt != updated.StartAt {
+		if err := p.addToIndex(kvUpcomingIndexKey, updated.ID); err != nil {
+			p.API.LogError("[Kontur] Failed to index upcoming meeting", "meeting_id", updated.ID, "error", err.Error())
+		}
+	}
+}
+
+// meetingChanges returns the diff of old vs new values of the editable fields
+func meetingChanges(old, updated *Meeting) map[string]interface{} {
+	changes := map[string]interface{}{}
+	diff := func(field string, oldValue, newValue interface{}) {
+		changes[field] = map[string]interface{}{"old": oldValue, "new": newValue}
+	}
+
+	if old.StartAt != updated.StartAt {
+		diff("start_time_utc", old.StartTime().UTC().Format(time.RFC3339), updated.StartTime().UTC().Format(time.RFC3339))
+	}
+	if old.DurationMinutes != updated.DurationMinutes {
+		diff("duration_minutes", old.DurationMinutes, updated.DurationMinutes)
+	}
+	if old.Title != updated.Title {
+		diff("title", old.Title, updated.Title)
+	}
+	if !sameIDs(old.ParticipantIDs, updated.ParticipantIDs) {
+		diff("participant_ids", old.ParticipantIDs, updated.ParticipantIDs)
+	}
+	return changes
+}
+
+// sameIDs reports whether both lists contain the same IDs regardless of order
+func sameIDs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[string]int, len(a))
+	for _, id := range a {
+		seen[id]++
+	}
+	for _, id := range b {
+		if seen[id] == 0 {
+			return false
+		}
+		seen[id]--
+	}
+	return true
+}
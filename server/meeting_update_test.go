@@ -0,0 +1,112 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/mattermost/mattermost-server/v6/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newUpdateTestPlugin stores a scheduled meeting and returns the copy a request handler would have loaded
+func newUpdateTestPlugin(t *testing.T) (*Plugin, *plugintest.API, *Meeting) {
+	p, api := newTestPlugin(t)
+	p.configuration.MeetingProvider = ProviderJitsi
+	api.On("GetUser", "organizer").Return(&model.User{Id: "organizer", Locale: "ru"}, nil)
+
+	meeting := &Meeting{
+		ID:              "meeting1",
+		OperationType:   OperationScheduledMeeting,
+		Status:          MeetingStatusScheduled,
+		Title:           "Планёрка",
+		ChannelID:       "channel",
+		OrganizerID:     "organizer",
+		StartAt:         model.GetMillisForTime(time.Now().Add(time.Hour)),
+		DurationMinutes: 30,
+		Timezone:        DefaultTimezone,
+		RoomURL:         "https://meet.example.com/room",
+	}
+	require.NoError(t, p.updateMeeting(meeting))
+	stale := *meeting
+	return p, api, &stale
+}
+
+func TestUpdateMeetingDetailsKeepsConcurrentUpdates(t *testing.T) {
+	p, _, meeting := newUpdateTestPlugin(t)
+
+	// Provider events and the reminder job write to the meeting after the request has loaded it
+	concurrent := *meeting
+	concurrent.LiveStatus = LiveStatusStarted
+	concurrent.ParticipantCount = 4
+	concurrent.RemindersSent = []int{15}
+	require.NoError(t, p.updateMeeting(&concurrent))
+
+	title := "Ретро"
+	changes, reqErr := p.updateMeetingDetails(meeting, &MeetingUpdateRequest{Title: &title}, "organizer")
+	require.Nil(t, reqErr)
+	assert.Contains(t, changes, "title")
+
+	stored, err := p.getMeeting(meeting.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Ретро", stored.Title)
+	assert.Equal(t, LiveStatusStarted, stored.LiveStatus)
+	assert.Equal(t, 4, stored.ParticipantCount)
+	assert.Equal(t, []int{15}, stored.RemindersSent)
+	assert.Equal(t, 1, stored.Sequence)
+	assert.Equal(t, *stored, *meeting)
+}
+
+func TestUpdateMeetingDetailsRejectsMeetingCancelledMeanwhile(t *testing.T) {
+	p, _, meeting := newUpdateTestPlugin(t)
+
+	cancelled := *meeting
+	cancelled.Status = MeetingStatusCancelled
+	require.NoError(t, p.updateMeeting(&cancelled))
+
+	title := "Ретро"
+	_, reqErr := p.updateMeetingDetails(meeting, &MeetingUpdateRequest{Title: &title}, "organizer")
+	require.NotNil(t, reqErr)
+	assert.Equal(t, http.StatusConflict, reqErr.StatusCode)
+
+	stored, err := p.getMeeting(meeting.ID)
+	require.NoError(t, err)
+	assert.Equal(t, MeetingStatusCancelled, stored.Status)
+	assert.Equal(t, "Планёрка", stored.Title)
+}
+
+func TestUpdateMeetingDetailsRefusesRescheduleOntoBusyTime(t *testing.T) {
+	p, api, meeting := newUpdateTestPlugin(t)
+	busyStart := time.Now().Add(3 * time.Hour).Truncate(time.Minute)
+	require.NoError(t, p.saveMeeting(&Meeting{
+		ID:              "busy",
+		OperationType:   OperationScheduledMeeting,
+		Status:          MeetingStatusScheduled,
+		Title:           "Ретро",
+		ChannelID:       "channel",
+		OrganizerID:     "organizer",
+		StartAt:         model.GetMillisForTime(busyStart),
+		DurationMinutes: 60,
+		Timezone:        DefaultTimezone,
+	}))
+	api.On("GetChannelMember", "channel", "organizer").Return(&model.ChannelMember{ChannelId: "channel", UserId: "organizer"}, nil)
+	update := &MeetingUpdateRequest{StartAt: busyStart.Add(15 * time.Minute).Format(time.RFC3339)}
+
+	_, reqErr := p.updateMeetingDetails(meeting, update, "organizer")
+
+	require.NotNil(t, reqErr)
+	assert.Equal(t, http.StatusConflict, reqErr.StatusCode)
+	require.Len(t, reqErr.Conflicts, 1)
+	assert.Equal(t, "busy", reqErr.Conflicts[0].MeetingID)
+	stored, err := p.getMeeting(meeting.ID)
+	require.NoError(t, err)
+	assert.Equal(t, meeting.StartAt, stored.StartAt)
+
+	update.Force = true
+	changes, reqErr := p.updateMeetingDetails(meeting, update, "organizer")
+
+	require.Nil(t, reqErr)
+	assert.Contains(t, changes, "start_time_utc")
+}
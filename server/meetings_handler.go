@@ -0,0 +1,206 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// handleMeetingRoutes routes /api/meetings/{id}/... requests
+func (p *Plugin) handleMeetingRoutes(w http.ResponseWriter, r *http.Request, userID string) {
+	// Messages follow the acting user's Mattermost language
+	var locale string
+
+	// Recover from panic
+	defer func() {
+		if rec := recover(); rec != nil {
+			if p != nil && p.API != nil {
+				p.API.LogError("[Kontur] Panic recovered", "panic", fmt.Sprintf("%v", rec))
+			}
+			if w.Header().Get("Content-Type") == "" {
+				writeErrorResponse(w, http.StatusInternalServerError, RequestFieldGeneral,
+					tr(locale, "request.internal_error", rec))
+			}
+		}
+	}()
+	locale = p.userLocale(userID)
+
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/meetings/"), "/"), "/")
+	if len(parts) == 0 || parts[0] == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	// Collection routes go before the meeting lookup
+	if len(parts) == 1 && parts[0] == SuggestSlotsPath {
+		p.handleSuggestSlots(w, r, userID, locale)
+		return
+	}
+
+	meeting, err := p.getMeeting(parts[0])
+	if err != nil {
+		if err == ErrMeetingNotFound {
+			writeErrorResponse(w, http.StatusNotFound, RequestFieldMeetingID, tr(locale, "meeting.not_found_id", parts[0]))
+			return
+		}
+		p.API.LogError("[Kontur] Failed to load meeting", "meeting_id", parts[0], "error", err.Error())
+		writeErrorResponse(w, http.StatusInternalServerError, RequestFieldGeneral, tr(locale, "meeting.load_failed"))
+		return
+	}
+
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		p.handleGetMeeting(w, r, userID, locale, meeting)
+	case len(parts) == 1:
+		p.handleUpdateMeeting(w, r, userID, locale, meeting)
+	case len(parts) == 2 && parts[1] == "cancel":
+		p.handleCancelMeeting(w, r, userID, locale, meeting)
+	case len(parts) == 2 && parts[1] == "ics":
+		p.handleMeetingICS(w, r, userID, locale, meeting)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// handleGetMeeting handles GET /api/meetings/{id}: the stored meeting and, with ?refresh=true, the provider room status
+func (p *Plugin) handleGetMeeting(w http.ResponseWriter, r *http.Request, userID, locale string, meeting *Meeting) {
+	if _, appErr := p.API.GetChannelMember(meeting.ChannelID, userID); appErr != nil {
+		writeErrorResponse(w, http.StatusForbidden, RequestFieldChannelID, tr(locale, "access.not_meeting_channel_member"))
+		return
+	}
+
+	// Asking the provider is a webhook round trip, so it is only done on explicit request
+	providerStatus := ProviderStatusUnknown
+	if r.URL.Query().Get("refresh") == "true" {
+		if provider, reqErr := p.getMeetingProvider(locale); reqErr == nil {
+			status, err := provider.GetStatus(meeting)
+			if err != nil {
+				p.API.LogWarn("[Kontur] Failed to get meeting status from provider", "meeting_id", meeting.ID, "error", err.Error())
+			} else {
+				providerStatus = status
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	response := map[string]interface{}{
+		"meeting":         meeting,
+		"provider_status": providerStatus,
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		p.API.LogError("[Kontur] Failed to encode meeting response", "error", err.Error())
+	}
+}
+
+// handleUpdateMeeting handles PATCH /api/meetings/{id}
+func (p *Plugin) handleUpdateMeeting(w http.ResponseWriter, r *http.Request, userID, locale string, meeting *Meeting) {
+	if r.Method != http.MethodPatch {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, RequestFieldGeneral, tr(locale, "meeting.method_patch"))
+		return
+	}
+
+	var update MeetingUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		p.API.LogError("[Kontur] Failed to parse JSON", "error", err.Error())
+		writeErrorResponse(w, http.StatusBadRequest, RequestFieldGeneral, tr(locale, "request.invalid_json", err.Error()))
+		return
+	}
+
+	if !p.canManageMeeting(userID, meeting) {
+		p.API.LogWarn("[Kontur] Update forbidden", "meeting_id", meeting.ID, RequestFieldUserID, userID)
+		writeErrorResponse(w, http.StatusForbidden, RequestFieldGeneral, tr(locale, "meeting.update_forbidden"))
+		return
+	}
+
+	// Re-run the duration and title validations of the schedule request
+	req := update.scheduleRequest(meeting)
+	req.locale = locale
+	if errors := validateScheduleFields(req); len(errors) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{"errors": errors})
+		return
+	}
+
+	changes, reqErr := p.updateMeetingDetails(meeting, &update, userID)
+	if reqErr != nil {
+		writeErrorResponse(w, reqErr.StatusCode, reqErr.Field, reqErr.Message)
+		return
+	}
+
+	message := tr(locale, "meeting.updated")
+	if len(changes) == 0 {
+		message = tr(locale, "meeting.unchanged")
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	response := map[string]interface{}{
+		"status":     "success",
+		"message":    message,
+		"meeting_id": meeting.ID,
+		"room_url":   meeting.RoomURL,
+		"changes":    changes,
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		p.API.LogError("[Kontur] Failed to encode success response", "error", err.Error())
+	}
+}
+
+// handleCancelMeeting handles POST /api/meetings/{id}/cancel
+func (p *Plugin) handleCancelMeeting(w http.ResponseWriter, r *http.Request, userID, locale string, meeting *Meeting) {
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, RequestFieldGeneral, tr(locale, "request.method_not_allowed"))
+		return
+	}
+
+	if !p.canManageMeeting(userID, meeting) {
+		p.API.LogWarn("[Kontur] Cancel forbidden", "meeting_id", meeting.ID, RequestFieldUserID, userID)
+		writeErrorResponse(w, http.StatusForbidden, RequestFieldGeneral, tr(locale, "meeting.cancel_forbidden"))
+		return
+	}
+
+	// ?series=true stops the whole recurring series, including upcoming occurrences
+	if r.URL.Query().Get("series") == "true" {
+		if meeting.SeriesID == "" {
+			writeErrorResponse(w, http.StatusBadRequest, RequestFieldMeetingID, tr(locale, "meeting.not_recurring"))
+			return
+		}
+		if reqErr := p.cancelSeries(meeting, userID); reqErr != nil {
+			writeErrorResponse(w, reqErr.StatusCode, reqErr.Field, reqErr.Message)
+			return
+		}
+		p.writeCancelResponse(w, meeting, tr(locale, "meeting.series_cancelled"))
+		return
+	}
+
+	if meeting.IsCancelled() {
+		writeErrorResponse(w, http.StatusConflict, RequestFieldGeneral, tr(locale, "meeting.already_cancelled"))
+		return
+	}
+
+	if reqErr := p.cancelMeeting(meeting, userID); reqErr != nil {
+		writeErrorResponse(w, reqErr.StatusCode, reqErr.Field, reqErr.Message)
+		return
+	}
+	p.writeCancelResponse(w, meeting, tr(locale, "meeting.cancelled"))
+}
+
+// writeCancelResponse answers a successful cancellation
+func (p *Plugin) writeCancelResponse(w http.ResponseWriter, meeting *Meeting, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	response := map[string]interface{}{
+		"status":     "success",
+		"message":    message,
+		"meeting_id": meeting.ID,
+	}
+	if meeting.SeriesID != "" {
+		response["series_id"] = meeting.SeriesID
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		p.API.LogError("[Kontur] Failed to encode success response", "error", err.Error())
+	}
+}
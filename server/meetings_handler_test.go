@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetMeetingQueriesProviderOnlyOnRefresh(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		query      string
+		webhook    int
+		wantStatus string
+		wantCalls  int32
+	}{
+		{"stored data only", "", http.StatusOK, ProviderStatusUnknown, 0},
+		{"refresh", "?refresh=true", http.StatusOK, "active", 1},
+		{"refresh is not retried", "?refresh=true", http.StatusBadGateway, ProviderStatusUnknown, 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				w.WriteHeader(tc.webhook)
+				w.Write([]byte(`{"meeting_status":"active"}`))
+			}))
+			defer server.Close()
+
+			p, api := newTestPlugin(t)
+			p.configuration.WebhookURL = server.URL
+			require.NoError(t, p.updateMeeting(&Meeting{ID: "meeting1", Status: MeetingStatusScheduled, ChannelID: "channel", OrganizerID: "organizer"}))
+			api.On("GetChannelMember", "channel", "member").Return(&model.ChannelMember{ChannelId: "channel", UserId: "member"}, nil)
+			api.On("GetUser", "member").Return(&model.User{Id: "member", Locale: "ru"}, nil)
+
+			w := serve(p, http.MethodGet, "/api/meetings/meeting1"+tc.query, "member", "")
+
+			require.Equal(t, http.StatusOK, w.Code)
+			var response struct {
+				ProviderStatus string `json:"provider_status"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tc.wantStatus, response.ProviderStatus)
+			assert.Equal(t, tc.wantCalls, atomic.LoadInt32(&calls))
+		})
+	}
+}
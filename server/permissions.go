@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+)
+
+// Participant scope values (setting ParticipantScope)
+const (
+	ParticipantScopeAny     = "any"
+	ParticipantScopeTeam    = "team"
+	ParticipantScopeChannel = "channel"
+)
+
+// checkChannelAccess verifies that the user is a channel member allowed to create posts there
+func (p *Plugin) checkChannelAccess(userID string, channel *model.Channel, locale string) error {
+	if _, appErr := p.API.GetChannelMember(channel.Id, userID); appErr != nil {
+		p.API.LogWarn("[Kontur] User is not a channel member",
+			"user_id", userID,
+			"channel_id", channel.Id,
+			"error", appErr.Error())
+		return fmt.Errorf("%s", tr(locale, "access.not_channel_member"))
+	}
+
+	if !p.API.HasPermissionToChannel(userID, channel.Id, model.PermissionCreatePost) {
+		p.API.LogWarn("[Kontur] User has no create_post permission in channel",
+			"user_id", userID,
+			"channel_id", channel.Id)
+		return fmt.Errorf("%s", tr(locale, "access.no_post_permission"))
+	}
+
+	return nil
+}
+
+// checkParticipantsAccess verifies that all participants belong to the configured scope
+func (p *Plugin) checkParticipantsAccess(participants []*model.User, channel *model.Channel, teamID, locale string) error {
+	config := p.getConfiguration()
+	scope := ParticipantScopeAny
+	if config != nil && config.ParticipantScope != "" {
+		scope = config.ParticipantScope
+	}
+
+	switch scope {
+	case ParticipantScopeChannel:
+		for _, user := range participants {
+			if _, appErr := p.API.GetChannelMember(channel.Id, user.Id); appErr != nil {
+				p.API.LogWarn("[Kontur] Participant is not a channel member",
+					"user_id", user.Id,
+					"channel_id", channel.Id)
+				return fmt.Errorf("%s", tr(locale, "access.participant_not_channel_member", user.Username))
+			}
+		}
+	case ParticipantScopeTeam:
+		// DM and group channels have no team, fall back to the team from the request
+		if channel.TeamId != "" {
+			teamID = channel.TeamId
+		}
+		if teamID == "" {
+			p.API.LogWarn("[Kontur] Team is unknown, skipping participant team check", "channel_id", channel.Id)
+			return nil
+		}
+		for _, user := range participants {
+			member, appErr := p.API.GetTeamMember(teamID, user.Id)
+			if appErr != nil || member == nil || member.DeleteAt != 0 {
+				p.API.LogWarn("[Kontur] Participant is not a team member",
+					"user_id", user.Id,
+					"team_id", teamID)
+				return fmt.Errorf("%s", tr(locale, "access.participant_not_team_member", user.Username))
+			}
+		}
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/mattermost/mattermost-server/v6/plugin"
 )
@@ -12,13 +13,35 @@ import (
 type Plugin struct {
 	plugin.MattermostPlugin
 	configuration *Configuration
+
+	// botUserID is the Kontur.Talk bot used for reminders and announcements
+	botUserID string
+
+	// jobsStop and jobsDone control the background job loop
+	jobsStop chan struct{}
+	jobsDone chan struct{}
 }
 
 // Configuration contains the plugin settings
 type Configuration struct {
-	WebhookURL   string
-	OpenInNewTab bool
-	ServiceName  string
+	WebhookURL         string
+	OpenInNewTab       bool
+	ServiceName        string
+	ParticipantScope   string
+	ReminderMinutes    string
+	PostAsBot          bool
+	MeetingProvider    string
+	JitsiURLTemplate   string
+	JitsiJWTSecret     string
+	JitsiJWTAppID      string
+	WebhookMaxRetries  int
+	WebhookSecret      string
+	WebhookAsync       bool
+	AttachTranscript   bool
+	AttachICS          bool
+	BusyLookup         bool
+	ReferenceTimezones string
+	LocalTimeNotice    string
 }
 
 // OnActivate is called when the plugin is activated
@@ -36,17 +59,33 @@ func (p *Plugin) OnActivate() error {
 
 	// Check that configuration is valid
 	config := p.getConfiguration()
-	if config.WebhookURL == "" {
-		p.API.LogWarn("WebhookURL is not configured")
+	if !p.isProviderConfigured() {
+		p.API.LogWarn("Meeting provider is not configured", "provider", config.MeetingProvider)
 	} else {
-		p.API.LogDebug("Plugin configured", "webhook_url", config.WebhookURL)
+		p.API.LogDebug("Plugin configured", "provider", config.MeetingProvider, "webhook_url", config.WebhookURL)
+	}
+
+	// Ensure the bot account used for reminders and announcements
+	botUserID, err := p.ensureBotUser()
+	if err != nil {
+		return fmt.Errorf("failed to ensure bot user: %w", err)
+	}
+	p.botUserID = botUserID
+
+	// Register the /meeting slash command
+	if err := p.API.RegisterCommand(getCommand()); err != nil {
+		return fmt.Errorf("failed to register /%s command: %w", CommandTrigger, err)
 	}
 
+	// Start reminders and other periodic jobs
+	p.startBackgroundJobs()
+
 	return nil
 }
 
 // OnDeactivate is called when the plugin is deactivated
 func (p *Plugin) OnDeactivate() error {
+	p.stopBackgroundJobs()
 	p.API.LogInfo("Kontur.Talk Meeting plugin deactivated")
 	return nil
 }
@@ -77,13 +116,45 @@ func (p *Plugin) ServeHTTP(c *plugin.Context, w http.ResponseWriter, r *http.Req
 		}
 	}()
 
+	// Provider callbacks carry their own signed token instead of a Mattermost session
+	switch r.URL.Path {
+	case CallbackPathMeetingCreated:
+		p.handleMeetingCreatedCallback(w, r)
+		return
+	case EventsPath:
+		p.handleMeetingEvent(w, r)
+		return
+	}
+
+	// The acting user always comes from the header injected by the Mattermost server
+	userID := r.Header.Get(HeaderMattermostUserID)
+	if userID == "" {
+		p.API.LogWarn("[Kontur] Unauthenticated request rejected", "path", r.URL.Path, "method", r.Method)
+		// There is no profile to take the language from, so the browser's one is used
+		locale := normalizeLocale(r.Header.Get("Accept-Language"))
+		writeErrorResponse(w, http.StatusUnauthorized, RequestFieldGeneral, tr(locale, "request.unauthorized"))
+		return
+	}
+
 	// Route requests based on path
 	switch r.URL.Path {
 	case "/config":
 		p.handleGetConfig(w, r)
 	case "/api/schedule-meeting":
-		p.handleScheduleMeeting(w, r)
+		p.handleScheduleMeeting(w, r, userID)
+	case "/api/instant-call":
+		p.handleInstantCall(w, r, userID)
+	case "/api/dialogs/reschedule":
+		p.handleRescheduleDialog(w, r, userID)
 	default:
+		if strings.HasPrefix(r.URL.Path, "/api/meetings/") {
+			p.handleMeetingRoutes(w, r, userID)
+			return
+		}
+		if action := strings.TrimPrefix(r.URL.Path, "/api/actions/"); action != r.URL.Path {
+			p.handlePostAction(w, r, userID, action)
+			return
+		}
 		http.NotFound(w, r)
 	}
 }
@@ -114,11 +185,12 @@ func (p *Plugin) handleGetConfig(w http.ResponseWriter, r *http.Request) {
 	// Set response headers
 	w.Header().Set("Content-Type", "application/json")
 
-	// Return configuration as JSON with snake_case keys
+	// Return configuration as JSON with snake_case keys.
+	// Provider settings stay on the server: clients only need to know whether meetings can be created.
 	response := map[string]interface{}{
-		"webhook_url":     config.WebhookURL,
-		"open_in_new_tab": config.OpenInNewTab,
-		"service_name":    config.ServiceName,
+		"webhook_configured": p.isProviderConfigured(),
+		"open_in_new_tab":    config.OpenInNewTab,
+		"service_name":       config.ServiceName,
 	}
 
 	if err := json.NewEncoder(w).Encode(response); err != nil {
@@ -141,9 +213,13 @@ func (p *Plugin) getConfiguration() *Configuration {
 		p.API.LogError("Failed to load configuration", "error", err.Error())
 		// Return default configuration on error
 		return &Configuration{
-			WebhookURL:   "",
-			OpenInNewTab: true,
-			ServiceName:  "",
+			WebhookURL:        "",
+			OpenInNewTab:      true,
+			ServiceName:       "",
+			ParticipantScope:  ParticipantScopeAny,
+			ReminderMinutes:   DefaultReminderMinutes,
+			MeetingProvider:   ProviderN8N,
+			WebhookMaxRetries: DefaultWebhookRetries,
 		}
 	}
 
@@ -152,7 +228,10 @@ func (p *Plugin) getConfiguration() *Configuration {
 }
 
 // handleScheduleMeeting handles the schedule meeting endpoint
-func (p *Plugin) handleScheduleMeeting(w http.ResponseWriter, r *http.Request) {
+func (p *Plugin) handleScheduleMeeting(w http.ResponseWriter, r *http.Request, userID string) {
+	// Messages follow the acting user's Mattermost language
+	var locale string
+
 	// Recover from panic
 	defer func() {
 		if rec := recover(); rec != nil {
@@ -161,7 +240,7 @@ func (p *Plugin) handleScheduleMeeting(w http.ResponseWriter, r *http.Request) {
 			}
 			if w.Header().Get("Content-Type") == "" {
 				writeErrorResponse(w, http.StatusInternalServerError, "general",
-					fmt.Sprintf("Внутренняя ошибка сервера: %v", rec))
+					tr(locale, "request.internal_error", rec))
 			}
 		}
 	}()
@@ -171,133 +250,48 @@ func (p *Plugin) handleScheduleMeeting(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Plugin not initialized", http.StatusInternalServerError)
 		return
 	}
+	locale = p.userLocale(userID)
 
 	p.API.LogDebug("[Kontur] schedule-meeting called")
 
 	// Only allow POST requests
 	if r.Method != http.MethodPost {
 		p.API.LogWarn("[Kontur] Method not allowed", "method", r.Method)
-		writeErrorResponse(w, http.StatusMethodNotAllowed, RequestFieldGeneral, "Метод не разрешён. Используйте POST.")
+		writeErrorResponse(w, http.StatusMethodNotAllowed, RequestFieldGeneral, tr(locale, "request.method_not_allowed"))
 		return
 	}
 
 	// Step 1: Validate and parse request
-	req, ok := p.validateScheduleRequest(w, r)
+	req, ok := p.validateScheduleRequest(w, r, userID, locale)
 	if !ok {
 		return
 	}
 
-	// Step 2: Parse and validate date/time
-	scheduledAt, err := p.parseDateTime(req)
-	if err != nil {
-		p.API.LogError("[Kontur] Date/time validation failed", "error", err.Error())
-		writeErrorResponse(w, http.StatusBadRequest, RequestFieldStartAtLocal, err.Error())
-		return
-	}
-
-	// Step 3: Get user and channel
-	currentUser, channel, err := p.getUserAndChannel(req)
-	if err != nil {
-		p.API.LogError("[Kontur] Failed to get user/channel", "error", err.Error())
-		if currentUser == nil {
-			writeErrorResponse(w, http.StatusNotFound, RequestFieldUserID, fmt.Sprintf("Пользователь не найден: %s", req.UserID))
-		} else {
-			writeErrorResponse(w, http.StatusNotFound, RequestFieldChannelID, fmt.Sprintf("Канал не найден: %s", req.ChannelID))
-		}
+	// Steps 2-8: Create the meeting through the shared pipeline
+	meeting, reqErr := p.scheduleMeeting(req)
+	if reqErr != nil {
+		writeRequestError(w, reqErr)
 		return
 	}
 
-	// Step 4: Resolve participants
-	participants, err := p.resolveParticipants(req, channel)
-	if err != nil {
-		p.API.LogError("[Kontur] Failed to resolve participants", "error", err.Error())
-		writeErrorResponse(w, http.StatusBadRequest, RequestFieldParticipantIDs, err.Error())
-		return
-	}
-
-	// Step 5: Get configuration and check webhook URL
-	config := p.getConfiguration()
-	if config == nil || config.WebhookURL == "" {
-		p.API.LogError("[Kontur] Webhook URL not configured")
-		writeErrorResponse(w, http.StatusBadRequest, RequestFieldGeneral, "Webhook URL не настроен. Обратитесь к администратору.")
-		return
-	}
-
-	// Step 6: Build and send webhook
-	webhookPayload := p.buildWebhookPayload(req, currentUser, channel, participants, scheduledAt)
-	webhookData, err := p.sendWebhook(config.WebhookURL, webhookPayload)
-	if err != nil {
-		// Check if this is a structured n8n error
-		if webhookErr, ok := IsWebhookError(err); ok {
-			// Log with execution_id for debugging
-			if webhookErr.ExecutionID != "" {
-				p.API.LogError("[Kontur] Webhook returned error from n8n",
-					"error", webhookErr.Message,
-					"execution_id", webhookErr.ExecutionID,
-					"status_code", webhookErr.StatusCode)
-			} else {
-				p.API.LogError("[Kontur] Webhook returned error from n8n",
-					"error", webhookErr.Message,
-					"status_code", webhookErr.StatusCode)
-			}
-
-			// Return n8n error message to user
-			// Use the status code from n8n, but default to 400 if it's not a client error
-			statusCode := webhookErr.StatusCode
-			if statusCode < 400 || statusCode >= 500 {
-				statusCode = http.StatusBadRequest
-			}
-
-			writeErrorResponse(w, statusCode, RequestFieldGeneral, webhookErr.Message)
-			return
-		}
-
-		// Network or other non-n8n errors
-		p.API.LogError("[Kontur] Webhook request failed", "error", err.Error())
-
-		// Detailed error message for webhook failures
-		errorMsg := "Не удалось создать встречу.\n\n"
-		errorMsg += "🔌 Не удалось подключиться к вебхуку:\n"
-		errorMsg += config.WebhookURL
-		errorMsg += "\n\nПроверьте:\n"
-		errorMsg += "1. n8n запущен и доступен\n"
-		errorMsg += "2. Workflow активирован\n"
-		errorMsg += "3. URL указан правильно"
-
-		writeErrorResponse(w, http.StatusInternalServerError, RequestFieldGeneral, errorMsg)
-		return
-	}
-
-	// Step 7: Get room URL from webhook response
-	roomURL := ""
-	if url, ok := webhookData["room_url"].(string); ok {
-		roomURL = url
-	} else if url, ok := webhookData["meeting_url"].(string); ok {
-		roomURL = url
-	}
-
-	// Step 7.5: Validate room URL - don't create post without it
-	if roomURL == "" {
-		p.API.LogWarn("[Kontur] room_url пустой, пост не будет создан", "webhook_response", fmt.Sprintf("%+v", webhookData))
-		writeErrorResponse(w, http.StatusBadGateway, RequestFieldGeneral,
-			"Вебхук не вернул ссылку на комнату. Встреча не была создана.")
-		return
-	}
-
-	// Step 8: Create post in channel or thread
-	if err := p.createPost(channel, currentUser, participants, scheduledAt, req.DurationMinutes, roomURL, req.RootID, req); err != nil {
-		// Don't fail the request if post creation fails (meeting is already created)
-		p.API.LogWarn("[Kontur] Failed to create post, but meeting was created", "error", err.Error())
-	}
-
 	// Step 9: Return success response
-	p.API.LogInfo("[Kontur] Meeting scheduled successfully", "room_url", roomURL)
+	p.API.LogInfo("[Kontur] Meeting scheduled successfully", "room_url", meeting.RoomURL)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
+	message := tr(locale, "schedule.success")
+	if meeting.IsPending() {
+		message = tr(locale, "schedule.pending")
+	}
 	response := map[string]interface{}{
-		"status":   "success",
-		"message":  "Встреча успешно создана",
-		"room_url": roomURL,
+		"status":     "success",
+		"message":    message,
+		"room_url":   meeting.RoomURL,
+		"meeting_id": meeting.ID,
+		"pending":    meeting.IsPending(),
+	}
+	if meeting.SeriesID != "" {
+		response["series_id"] = meeting.SeriesID
+		response["recurrence"] = meeting.Recurrence
 	}
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		p.API.LogError("[Kontur] Failed to encode success response", "error", err.Error())
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/mattermost/mattermost-server/v6/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testAPI silences plugin logging, whose variadic calls can't be matched by the mock,
+// and keeps the KV store in memory
+type testAPI struct {
+	*plugintest.API
+	kv *memoryKV
+}
+
+func (testAPI) LogDebug(string, ...interface{}) {}
+func (testAPI) LogInfo(string, ...interface{})  {}
+func (testAPI) LogWarn(string, ...interface{})  {}
+func (testAPI) LogError(string, ...interface{}) {}
+
+func (a testAPI) KVGet(key string) ([]byte, *model.AppError) {
+	return a.kv.get(key), nil
+}
+
+func (a testAPI) KVSet(key string, value []byte) *model.AppError {
+	a.kv.set(key, value)
+	return nil
+}
+
+func (a testAPI) KVDelete(key string) *model.AppError {
+	a.kv.set(key, nil)
+	return nil
+}
+
+func (a testAPI) KVSetWithOptions(key string, value []byte, options model.PluginKVSetOptions) (bool, *model.AppError) {
+	return a.kv.setWithOptions(key, value, options), nil
+}
+
+// memoryKV is an in-memory plugin KV store with compare-and-set semantics
+type memoryKV struct {
+	mu   sync.Mutex
+	data map[string][]byte
+
+	// onGet, if set, runs after every read, e.g. to simulate a concurrent write
+	onGet func(key string)
+}
+
+func (kv *memoryKV) get(key string) []byte {
+	kv.mu.Lock()
+	value := kv.data[key]
+	kv.mu.Unlock()
+
+	if kv.onGet != nil {
+		kv.onGet(key)
+	}
+	return value
+}
+
+func (kv *memoryKV) set(key string, value []byte) {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	if value == nil {
+		delete(kv.data, key)
+		return
+	}
+	kv.data[key] = value
+}
+
+func (kv *memoryKV) setWithOptions(key string, value []byte, options model.PluginKVSetOptions) bool {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	if options.Atomic {
+		current, exists := kv.data[key]
+		if options.OldValue == nil && exists || options.OldValue != nil && !bytes.Equal(current, options.OldValue) {
+			return false
+		}
+	}
+	if value == nil {
+		delete(kv.data, key)
+		return true
+	}
+	kv.data[key] = value
+	return true
+}
+
+// onFirstMeetingRead replaces the stored meeting right after it is read for the first time,
+// simulating a write that races with the code under test
+func onFirstMeetingRead(t *testing.T, p *Plugin, kv *memoryKV, replacement *Meeting) {
+	done := false
+	kv.onGet = func(key string) {
+		if key != kvMeetingPrefix+replacement.ID || done {
+			return
+		}
+		done = true
+		require.NoError(t, p.updateMeeting(replacement))
+	}
+}
+
+// newTestPlugin returns a plugin backed by a mock API, an in-memory KV store and the default configuration
+func newTestPlugin(t *testing.T) (*Plugin, *plugintest.API) {
+	p, api, _ := newTestPluginWithKV(t)
+	return p, api
+}
+
+// newTestPluginWithKV is newTestPlugin that also exposes the KV store
+func newTestPluginWithKV(t *testing.T) (*Plugin, *plugintest.API, *memoryKV) {
+	api := &plugintest.API{}
+	t.Cleanup(func() { api.AssertExpectations(t) })
+
+	kv := &memoryKV{data: map[string][]byte{}}
+	p := &Plugin{configuration: &Configuration{
+		ParticipantScope:  ParticipantScopeAny,
+		ReminderMinutes:   DefaultReminderMinutes,
+		MeetingProvider:   ProviderN8N,
+		WebhookMaxRetries: DefaultWebhookRetries,
+	}}
+	p.SetAPI(testAPI{API: api, kv: kv})
+	return p, api, kv
+}
+
+func serve(p *Plugin, method, path, userID, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+	if userID != "" {
+		r.Header.Set(HeaderMattermostUserID, userID)
+	}
+	w := httptest.NewRecorder()
+	p.ServeHTTP(nil, w, r)
+	return w
+}
+
+func TestServeHTTPRequiresAuthentication(t *testing.T) {
+	for _, path := range []string{"/config", "/api/schedule-meeting", "/api/instant-call", "/api/meetings/abc"} {
+		t.Run(path, func(t *testing.T) {
+			p, _ := newTestPlugin(t)
+
+			w := serve(p, http.MethodPost, path, "", `{"user_id":"someone"}`)
+
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			assert.Contains(t, w.Body.String(), "Требуется авторизация в Mattermost")
+		})
+	}
+}
+
+func TestServeHTTPUnauthenticatedUsesBrowserLanguage(t *testing.T) {
+	p, _ := newTestPlugin(t)
+	r := httptest.NewRequest(http.MethodGet, "/config", nil)
+	r.Header.Set("Accept-Language", "en-US,en;q=0.9")
+	w := httptest.NewRecorder()
+
+	p.ServeHTTP(nil, w, r)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "Mattermost authentication required")
+}
+
+func TestServeHTTPRejectsForeignUserID(t *testing.T) {
+	const authUserID, otherUserID = "authuser", "otheruser"
+
+	for _, tc := range []struct {
+		path    string
+		locale  string
+		message string
+	}{
+		{"/api/schedule-meeting", "ru", "user_id не совпадает с текущим пользователем"},
+		{"/api/instant-call", "ru", "user_id не совпадает с текущим пользователем"},
+		{"/api/schedule-meeting", "en", "user_id does not match the current user"},
+		{"/api/instant-call", "en", "user_id does not match the current user"},
+	} {
+		t.Run(tc.path+"/"+tc.locale, func(t *testing.T) {
+			p, api := newTestPlugin(t)
+			api.On("GetUser", authUserID).Return(&model.User{Id: authUserID, Username: "auth", Locale: tc.locale}, nil)
+
+			w := serve(p, http.MethodPost, tc.path, authUserID, `{"user_id":"`+otherUserID+`","channel_id":"channel","duration_minutes":30}`)
+
+			require.Equal(t, http.StatusForbidden, w.Code)
+			assert.Contains(t, w.Body.String(), `"field":"user_id"`)
+			assert.Contains(t, w.Body.String(), tc.message)
+		})
+	}
+}
+
+func TestResolveActingUserID(t *testing.T) {
+	for _, tc := range []struct {
+		name, auth, body, want string
+		wantErr                bool
+	}{
+		{"body omitted", "u1", "", "u1", false},
+		{"body repeats header", "u1", "u1", "u1", false},
+		{"body names someone else", "u1", "u2", "", true},
+		{"no header", "", "u1", "", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := resolveActingUserID(tc.auth, tc.body)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
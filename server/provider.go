@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+)
+
+// Meeting provider names, selected by the MeetingProvider setting
+const (
+	ProviderN8N   = "n8n"
+	ProviderJitsi = "jitsi"
+)
+
+// ProviderStatusUnknown is reported when the provider can't tell the room status
+const ProviderStatusUnknown = "unknown"
+
+// MeetingProvider creates and manages video rooms behind plugin meetings
+type MeetingProvider interface {
+	// CreateMeeting creates a room for a new meeting
+	CreateMeeting(req *ProviderRequest) (*ProviderRoom, error)
+	// UpdateMeeting propagates changed meeting fields; a nil room keeps the current one
+	UpdateMeeting(req *ProviderRequest) (*ProviderRoom, error)
+	// CancelMeeting releases the room of a cancelled meeting
+	CancelMeeting(req *ProviderRequest) error
+	// GetStatus returns the provider-side status of the meeting room
+	GetStatus(meeting *Meeting) (string, error)
+}
+
+// ProviderRequest describes a meeting operation for a provider
+type ProviderRequest struct {
+	Meeting *Meeting
+	UserID  string // Acting user
+	Locale  string // Acting user's locale for error messages
+
+	// Set for CreateMeeting only
+	Schedule     *ScheduleRequest
+	Organizer    *model.User
+	Channel      *model.Channel
+	Participants []*model.User
+
+	// Set for UpdateMeeting only: {"field": {"old": ..., "new": ...}}
+	Changes map[string]interface{}
+}
+
+// ProviderRoom is the room a provider created or moved the meeting to
+type ProviderRoom struct {
+	URL string
+	ID  string
+
+	// Pending is set when the room is delivered later through /api/callback/meeting-created
+	Pending bool
+}
+
+// getMeetingProvider returns the provider selected in the plugin settings
+func (p *Plugin) getMeetingProvider(locale string) (MeetingProvider, *RequestError) {
+	name := p.getConfiguration().MeetingProvider
+	switch name {
+	case "", ProviderN8N:
+		return &webhookProvider{plugin: p}, nil
+	case ProviderJitsi:
+		return &jitsiProvider{plugin: p}, nil
+	default:
+		p.API.LogError("[Kontur] Unknown meeting provider", "provider", name)
+		return nil, &RequestError{StatusCode: http.StatusInternalServerError, Field: RequestFieldGeneral,
+			Message: tr(locale, "provider.unsupported", name)}
+	}
+}
+
+// isProviderConfigured reports whether the selected provider has the settings it needs to create meetings
+func (p *Plugin) isProviderConfigured() bool {
+	config := p.getConfiguration()
+	switch config.MeetingProvider {
+	case "", ProviderN8N:
+		return config.WebhookURL != ""
+	case ProviderJitsi:
+		return config.JitsiURLTemplate != ""
+	default:
+		return false
+	}
+}
+
+// providerRequestError converts a provider failure into a client error prefixed with the failureKey message
+func providerRequestError(err error, locale, failureKey string) *RequestError {
+	var reqErr *RequestError
+	if errors.As(err, &reqErr) {
+		return reqErr
+	}
+	return &RequestError{StatusCode: http.StatusBadGateway, Field: RequestFieldGeneral,
+		Message: fmt.Sprintf("%s: %s", tr(locale, failureKey), err.Error())}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Placeholders of the JitsiURLTemplate setting
+const (
+	jitsiPlaceholderChannel = "{channel}"
+	jitsiPlaceholderRandom  = "{random}"
+)
+
+// Jitsi provider settings
+const (
+	jitsiRandomBytes    = 16             // 128 bits keep room names unguessable
+	jitsiJWTAudience    = "jitsi"        // Default "aud" claim expected by Jitsi Meet token auth
+	jitsiJWTGrace       = 2 * time.Hour  // Token stays valid this long after the meeting end
+	jitsiInstantCallTTL = 24 * time.Hour // Token lifetime for meetings without duration
+)
+
+var reJitsiUnsafe = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// jitsiProvider generates Jitsi-style room links locally, without any external automation
+type jitsiProvider struct {
+	plugin *Plugin
+}
+
+// CreateMeeting fills the URL template with the channel name and a random suffix
+func (j *jitsiProvider) CreateMeeting(req *ProviderRequest) (*ProviderRoom, error) {
+	// Occurrences of a series share its room, only the token is issued per occurrence
+	if series := req.Schedule.series; series != nil && series.RoomURL != "" {
+		return j.resignRoom(series.RoomURL, series.RoomID, req.Meeting)
+	}
+
+	config := j.plugin.getConfiguration()
+	template := strings.TrimSpace(config.JitsiURLTemplate)
+	if template == "" {
+		j.plugin.API.LogError("[Kontur] Jitsi URL template not configured")
+		return nil, &RequestError{StatusCode: http.StatusBadRequest, Field: RequestFieldGeneral,
+			Message: tr(req.Locale, "provider.template_missing")}
+	}
+	if !strings.Contains(template, jitsiPlaceholderRandom) {
+		j.plugin.API.LogError("[Kontur] Jitsi URL template has no random part", "template", template)
+		return nil, &RequestError{StatusCode: http.StatusInternalServerError, Field: RequestFieldGeneral,
+			Message: tr(req.Locale, "provider.template_no_random")}
+	}
+
+	random, err := randomRoomSuffix()
+	if err != nil {
+		return nil, err
+	}
+
+	channelName := ""
+	if req.Channel != nil {
+		channelName = req.Channel.Name
+	}
+	roomURL := strings.NewReplacer(
+		jitsiPlaceholderChannel, sanitizeRoomName(channelName),
+		jitsiPlaceholderRandom, random,
+	).Replace(template)
+
+	parsed, err := url.Parse(roomURL)
+	if err != nil || parsed.Host == "" {
+		return nil, &RequestError{StatusCode: http.StatusInternalServerError, Field: RequestFieldGeneral,
+			Message: tr(req.Locale, "provider.template_invalid")}
+	}
+
+	room := &ProviderRoom{URL: roomURL, ID: path.Base(parsed.Path)}
+	if err := j.signRoom(room, req.Meeting); err != nil {
+		return nil, err
+	}
+	return room, nil
+}
+
+// UpdateMeeting keeps the room but re-signs the link when the meeting moves, so the token doesn't expire early
+func (j *jitsiProvider) UpdateMeeting(req *ProviderRequest) (*ProviderRoom, error) {
+	if j.plugin.getConfiguration().JitsiJWTSecret == "" {
+		return nil, nil
+	}
+	_, startChanged := req.Changes["start_time_utc"]
+	_, durationChanged := req.Changes["duration_minutes"]
+	if !startChanged && !durationChanged {
+		return nil, nil
+	}
+
+	return j.resignRoom(req.Meeting.RoomURL, req.Meeting.RoomID, req.Meeting)
+}
+
+// CancelMeeting does nothing: Jitsi rooms exist only while someone is in them
+func (j *jitsiProvider) CancelMeeting(req *ProviderRequest) error {
+	return nil
+}
+
+// GetStatus is not available without the Jitsi server API
+func (j *jitsiProvider) GetStatus(meeting *Meeting) (string, error) {
+	return ProviderStatusUnknown, nil
+}
+
+// resignRoom replaces the token of an existing room link with one valid for the meeting
+func (j *jitsiProvider) resignRoom(roomURL, roomID string, meeting *Meeting) (*ProviderRoom, error) {
+	parsed, err := url.Parse(roomURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid room URL: %w", err)
+	}
+	query := parsed.Query()
+	query.Del("jwt")
+	parsed.RawQuery = query.Encode()
+
+	room := &ProviderRoom{URL: parsed.String(), ID: roomID}
+	if err := j.signRoom(room, meeting); err != nil {
+		return nil, err
+	}
+	return room, nil
+}
+
+// signRoom appends a JWT for the room when a secret is configured
+func (j *jitsiProvider) signRoom(room *ProviderRoom, meeting *Meeting) error {
+	config := j.plugin.getConfiguration()
+	if config.JitsiJWTSecret == "" {
+		return nil
+	}
+
+	parsed, err := url.Parse(room.URL)
+	if err != nil {
+		return fmt.Errorf("invalid room URL: %w", err)
+	}
+
+	expiresAt := meeting.EndTime().Add(jitsiJWTGrace)
+	if meeting.DurationMinutes == 0 {
+		expiresAt = meeting.StartTime().Add(jitsiInstantCallTTL)
+	}
+
+	appID := config.JitsiJWTAppID
+	if appID == "" {
+		appID = PluginID
+	}
+
+	token, err := signHS256JWT(map[string]interface{}{
+		"aud":  jitsiJWTAudience,
+		"iss":  appID,
+		"sub":  parsed.Host,
+		"room": room.ID,
+		"nbf":  time.Now().Add(-time.Minute).Unix(),
+		"exp":  expiresAt.Unix(),
+	}, config.JitsiJWTSecret)
+	if err != nil {
+		return err
+	}
+
+	query := parsed.Query()
+	query.Set("jwt", token)
+	parsed.RawQuery = query.Encode()
+	room.URL = parsed.String()
+	return nil
+}
+
+// signHS256JWT builds a compact JWT signed with HMAC-SHA256
+func signHS256JWT(claims map[string]interface{}, secret string) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "HS256", "typ": "JWT"})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JWT header: %w", err)
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JWT claims: %w", err)
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signingInput))
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// randomRoomSuffix returns a random hex string for room names
+func randomRoomSuffix() (string, error) {
+	buf := make([]byte, jitsiRandomBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate room name: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// sanitizeRoomName turns a channel name into a URL-safe room name part
+func sanitizeRoomName(name string) string {
+	name = reJitsiUnsafe.ReplaceAllString(strings.ToLower(name), "-")
+	name = strings.Trim(name, "-")
+	if name == "" {
+		return "meeting"
+	}
+	return name
+}
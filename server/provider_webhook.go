@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Webhook operations that don't create a meeting
+const (
+	OperationMeetingStatus = "meeting_status"
+)
+
+// webhookProvider delegates rooms to the n8n webhook
+type webhookProvider struct {
+	plugin *Plugin
+}
+
+// CreateMeeting sends the scheduled_meeting/instant_call payload and reads the room from the response
+// or, in async mode, leaves the meeting pending until the callback
+func (w *webhookProvider) CreateMeeting(req *ProviderRequest) (*ProviderRoom, error) {
+	p := w.plugin
+	webhookURL := p.getConfiguration().WebhookURL
+	if webhookURL == "" {
+		p.API.LogError("[Kontur] Webhook URL not configured")
+		return nil, &RequestError{StatusCode: http.StatusBadRequest, Field: RequestFieldGeneral,
+			Message: tr(req.Locale, "provider.webhook_not_configured")}
+	}
+
+	payload := p.buildWebhookPayload(req.Schedule, req.Organizer, req.Channel, req.Participants, req.Meeting.StartTime())
+	payload["meeting_id"] = req.Meeting.ID
+
+	// In async mode the webhook answers right away and delivers the room to the callback later
+	async := false
+	if p.getConfiguration().WebhookAsync {
+		callbackURL, token, err := p.meetingCallback(req.Meeting.ID)
+		if err != nil {
+			p.API.LogWarn("[Kontur] Async mode unavailable, waiting for the room synchronously", "error", err.Error())
+		} else {
+			payload["callback_url"] = callbackURL
+			payload["callback_token"] = token
+			async = true
+		}
+	}
+
+	webhookData, err := p.sendWebhook(webhookURL, payload)
+	if err != nil {
+		return nil, p.webhookRequestError(err, webhookURL, req.Locale, "provider.create_failed")
+	}
+
+	// Validate room URL - don't create post without it
+	roomURL := extractRoomURL(webhookData)
+	if series := req.Schedule.series; roomURL == "" && series != nil && series.RoomURL != "" {
+		// The webhook kept the series room
+		return &ProviderRoom{URL: series.RoomURL, ID: series.RoomID}, nil
+	}
+	if roomURL == "" && async {
+		return &ProviderRoom{Pending: true}, nil
+	}
+	if roomURL == "" {
+		p.API.LogWarn("[Kontur] room_url пустой, пост не будет создан", "webhook_response", fmt.Sprintf("%+v", webhookData))
+		return nil, &RequestError{StatusCode: http.StatusBadGateway, Field: RequestFieldGeneral,
+			Message: tr(req.Locale, "webhook.no_room_url")}
+	}
+
+	return &ProviderRoom{URL: roomURL, ID: extractRoomID(webhookData)}, nil
+}
+
+// UpdateMeeting sends the update_meeting payload with the changes; the webhook may return a new room
+func (w *webhookProvider) UpdateMeeting(req *ProviderRequest) (*ProviderRoom, error) {
+	p := w.plugin
+	webhookURL := p.getConfiguration().WebhookURL
+	if webhookURL == "" {
+		return nil, nil
+	}
+
+	payload := p.buildMeetingWebhookPayload(OperationUpdateMeeting, req.Meeting, req.UserID)
+	payload["changes"] = req.Changes
+	webhookData, err := p.sendWebhook(webhookURL, payload)
+	if err != nil {
+		return nil, p.webhookRequestError(err, webhookURL, req.Locale, "provider.update_failed")
+	}
+
+	roomURL := extractRoomURL(webhookData)
+	if roomURL == "" {
+		return nil, nil
+	}
+	return &ProviderRoom{URL: roomURL, ID: extractRoomID(webhookData)}, nil
+}
+
+// CancelMeeting sends the cancel_meeting payload with the room identifiers
+func (w *webhookProvider) CancelMeeting(req *ProviderRequest) error {
+	p := w.plugin
+	webhookURL := p.getConfiguration().WebhookURL
+	if webhookURL == "" {
+		return nil
+	}
+
+	payload := p.buildMeetingWebhookPayload(OperationCancelMeeting, req.Meeting, req.UserID)
+	if _, err := p.sendWebhook(webhookURL, payload); err != nil {
+		return p.webhookRequestError(err, webhookURL, req.Locale, "provider.cancel_failed")
+	}
+	return nil
+}
+
+// GetStatus asks the webhook for the room status (meeting_status field of the response).
+// A client is waiting for the answer, so the request is short and isn't retried.
+func (w *webhookProvider) GetStatus(meeting *Meeting) (string, error) {
+	p := w.plugin
+	webhookURL := p.getConfiguration().WebhookURL
+	if webhookURL == "" {
+		return ProviderStatusUnknown, nil
+	}
+
+	webhookData, err := p.sendWebhookWithin(webhookURL, p.buildMeetingWebhookPayload(OperationMeetingStatus, meeting, ""), ProviderStatusTimeout, 0)
+	if err != nil {
+		return "", err
+	}
+	if status, ok := webhookData["meeting_status"].(string); ok && status != "" {
+		return status, nil
+	}
+	return ProviderStatusUnknown, nil
+}
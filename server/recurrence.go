@@ -0,0 +1,295 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recurrence frequencies accepted in RecurrenceRequest
+const (
+	RecurrenceDaily    = "daily"
+	RecurrenceWeekdays = "weekdays"
+	RecurrenceWeekly   = "weekly"
+)
+
+// Recurrence limits
+const (
+	recurrenceMaxInterval = 52
+	recurrenceMaxCount    = 365
+	recurrenceSearchDays  = 3 * 366 // How far ahead the next occurrence is looked for
+)
+
+// RRULE weekday codes
+var rruleWeekdays = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+// RecurrenceRequest describes how a scheduled meeting repeats
+type RecurrenceRequest struct {
+	Frequency string   `json:"frequency"` // daily, weekdays or weekly
+	Interval  int      `json:"interval"`  // Every N days/weeks, 1 by default
+	Days      []string `json:"days"`      // Weekdays for weekly meetings: MO, TU, ..., SU
+	Until     string   `json:"until"`     // Last date (YYYY-MM-DD), inclusive
+	Count     int      `json:"count"`     // Total number of occurrences, including the first one
+	RRule     string   `json:"rrule"`     // RFC 5545 RRULE (FREQ=DAILY|WEEKLY, INTERVAL, BYDAY, UNTIL, COUNT), overrides the fields above
+}
+
+// RecurrenceRule is a parsed recurrence: a subset of RFC 5545 RRULE
+type RecurrenceRule struct {
+	Freq     string // DAILY or WEEKLY
+	Interval int
+	ByDay    []time.Weekday
+	Until    time.Time // Zero if unbounded
+	Count    int       // Zero if unbounded
+}
+
+// parseRecurrence builds the rule for a series whose first occurrence is start; errors are in the locale
+func parseRecurrence(req *RecurrenceRequest, start time.Time, locale string) (*RecurrenceRule, error) {
+	if req.RRule != "" {
+		rule, err := parseRRule(req.RRule, start.Location(), locale)
+		if err != nil {
+			return nil, err
+		}
+		return rule, rule.validate(start, locale)
+	}
+
+	rule := &RecurrenceRule{Interval: req.Interval, Count: req.Count}
+	switch strings.ToLower(req.Frequency) {
+	case RecurrenceDaily:
+		rule.Freq = "DAILY"
+	case RecurrenceWeekdays:
+		rule.Freq = "WEEKLY"
+		rule.ByDay = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	case RecurrenceWeekly:
+		rule.Freq = "WEEKLY"
+		for _, day := range req.Days {
+			weekday, ok := rruleWeekdays[strings.ToUpper(day)]
+			if !ok {
+				return nil, fmt.Errorf("%s", tr(locale, "recurrence.unknown_weekday", day))
+			}
+			rule.ByDay = append(rule.ByDay, weekday)
+		}
+	default:
+		return nil, fmt.Errorf("%s", tr(locale, "recurrence.unknown_frequency", req.Frequency))
+	}
+
+	if req.Until != "" {
+		until, err := time.ParseInLocation("2006-01-02", req.Until, start.Location())
+		if err != nil {
+			return nil, fmt.Errorf("%s", tr(locale, "recurrence.invalid_until_date", req.Until))
+		}
+		// The whole last day is included
+		rule.Until = until.AddDate(0, 0, 1).Add(-time.Second)
+	}
+
+	return rule, rule.validate(start, locale)
+}
+
+// parseRRule parses an RRULE string such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10"
+func parseRRule(value string, loc *time.Location, locale string) (*RecurrenceRule, error) {
+	rule := &RecurrenceRule{}
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	for _, part := range strings.Split(value, ";") {
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("%s", tr(locale, "recurrence.invalid_part", part))
+		}
+		key, val := strings.ToUpper(kv[0]), strings.ToUpper(kv[1])
+
+		switch key {
+		case "FREQ":
+			if val != "DAILY" && val != "WEEKLY" {
+				return nil, fmt.Errorf("%s", tr(locale, "recurrence.unsupported_freq"))
+			}
+			rule.Freq = val
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil {
+				return nil, fmt.Errorf("%s", tr(locale, "recurrence.invalid_interval", val))
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(val)
+			if err != nil {
+				return nil, fmt.Errorf("%s", tr(locale, "recurrence.invalid_count", val))
+			}
+			rule.Count = n
+		case "UNTIL":
+			until, err := parseRRuleUntil(val, loc, locale)
+			if err != nil {
+				return nil, err
+			}
+			rule.Until = until
+		case "BYDAY":
+			for _, day := range strings.Split(val, ",") {
+				weekday, ok := rruleWeekdays[day]
+				if !ok {
+					return nil, fmt.Errorf("%s", tr(locale, "recurrence.unsupported_byday", day))
+				}
+				rule.ByDay = append(rule.ByDay, weekday)
+			}
+		case "WKST":
+			// Weeks always start on Monday
+		default:
+			return nil, fmt.Errorf("%s", tr(locale, "recurrence.unsupported_part", key))
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, fmt.Errorf("%s", tr(locale, "recurrence.missing_freq"))
+	}
+	return rule, nil
+}
+
+// parseRRuleUntil parses UNTIL as a date (20250131) or UTC date-time (20250131T235959Z)
+func parseRRuleUntil(value string, loc *time.Location, locale string) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t.In(loc), nil
+	}
+	if t, err := time.ParseInLocation("20060102", value, loc); err == nil {
+		return t.AddDate(0, 0, 1).Add(-time.Second), nil
+	}
+	return time.Time{}, fmt.Errorf("%s", tr(locale, "recurrence.invalid_until", value))
+}
+
+// validate normalizes defaults and checks the rule limits
+func (r *RecurrenceRule) validate(start time.Time, locale string) error {
+	if r.Interval == 0 {
+		r.Interval = 1
+	}
+	if r.Interval < 1 || r.Interval > recurrenceMaxInterval {
+		return fmt.Errorf("%s", tr(locale, "recurrence.interval_range", recurrenceMaxInterval))
+	}
+	if r.Count < 0 || r.Count > recurrenceMaxCount {
+		return fmt.Errorf("%s", tr(locale, "recurrence.count_range", recurrenceMaxCount))
+	}
+	if !r.Until.IsZero() && r.Until.Before(start) {
+		return fmt.Errorf("%s", tr(locale, "recurrence.until_before_start"))
+	}
+	if r.Freq == "WEEKLY" && len(r.ByDay) == 0 {
+		r.ByDay = []time.Weekday{start.Weekday()}
+	}
+	sort.Slice(r.ByDay, func(i, j int) bool {
+		return (r.ByDay[i]+6)%7 < (r.ByDay[j]+6)%7
+	})
+	return nil
+}
+
+// nextAfter returns the first occurrence strictly after the given time, or false when the series is over.
+// generated is the number of occurrences already produced, counted against COUNT.
+func (r *RecurrenceRule) nextAfter(dtstart, after time.Time, generated int) (time.Time, bool) {
+	if r.Count > 0 && generated >= r.Count {
+		return time.Time{}, false
+	}
+
+	loc := dtstart.Location()
+	after = after.In(loc)
+	firstDay := time.Date(dtstart.Year(), dtstart.Month(), dtstart.Day(), 0, 0, 0, 0, loc)
+	firstMonday := firstDay.AddDate(0, 0, -int((firstDay.Weekday()+6)%7))
+
+	day := time.Date(after.Year(), after.Month(), after.Day(), 0, 0, 0, 0, loc)
+	if day.Before(firstDay) {
+		day = firstDay
+	}
+
+	for i := 0; i < recurrenceSearchDays; i, day = i+1, day.AddDate(0, 0, 1) {
+		if !r.matchesDay(day, firstDay, firstMonday) {
+			continue
+		}
+		candidate := time.Date(day.Year(), day.Month(), day.Day(), dtstart.Hour(), dtstart.Minute(), 0, 0, loc)
+		if !candidate.After(after) {
+			continue
+		}
+		if !r.Until.IsZero() && candidate.After(r.Until) {
+			return time.Time{}, false
+		}
+		return candidate, true
+	}
+	return time.Time{}, false
+}
+
+// matchesDay reports whether the rule produces an occurrence on the given day
+func (r *RecurrenceRule) matchesDay(day, firstDay, firstMonday time.Time) bool {
+	if len(r.ByDay) > 0 && !containsWeekday(r.ByDay, day.Weekday()) {
+		return false
+	}
+	// Calendar days between dates, independent of DST shifts
+	days := int(day.Sub(firstDay).Hours()/24 + 0.5)
+	if r.Freq == "DAILY" {
+		return days%r.Interval == 0
+	}
+	weeks := int(day.Sub(firstMonday).Hours()/24+0.5) / 7
+	return weeks%r.Interval == 0
+}
+
+// String formats the rule as an RFC 5545 RRULE value
+func (r *RecurrenceRule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, fmt.Sprintf("INTERVAL=%d", r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, weekday := range r.ByDay {
+			for code, d := range rruleWeekdays {
+				if d == weekday {
+					days = append(days, code)
+				}
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, fmt.Sprintf("COUNT=%d", r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Describe returns a short description in the locale, such as "каждые 2 недели: пн, ср"
+func (r *RecurrenceRule) Describe(locale string) string {
+	days := make([]string, 0, len(r.ByDay))
+	for _, weekday := range r.ByDay {
+		days = append(days, tr(locale, weekdayKeys[weekday]))
+	}
+
+	var text string
+	switch {
+	case r.Freq == "DAILY" && r.Interval == 1:
+		text = tr(locale, "recurrence.daily")
+	case r.Freq == "DAILY":
+		text = tr(locale, "recurrence.every_days", r.Interval, trPlural(locale, "recurrence.days", r.Interval))
+	case len(days) == 5 && r.Interval == 1 && !containsWeekday(r.ByDay, time.Saturday) && !containsWeekday(r.ByDay, time.Sunday):
+		text = tr(locale, "recurrence.weekdays")
+	case r.Interval == 1:
+		text = tr(locale, "recurrence.weekly", strings.Join(days, ", "))
+	default:
+		text = tr(locale, "recurrence.every_weeks", r.Interval, trPlural(locale, "recurrence.weeks", r.Interval), strings.Join(days, ", "))
+	}
+	if r.Count > 0 {
+		text += tr(locale, "recurrence.count", r.Count, trPlural(locale, "recurrence.times", r.Count))
+	}
+	if !r.Until.IsZero() {
+		text += tr(locale, "recurrence.until", r.Until.Format(tr(locale, "datetime.layout_date")))
+	}
+	return text
+}
+
+// containsWeekday reports whether the weekday is in the list
+func containsWeekday(days []time.Weekday, weekday time.Weekday) bool {
+	for _, d := range days {
+		if d == weekday {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// occurrences expands the rule from start into at most n occurrences, formatted as "2006-01-02 15:04 Mon"
+func occurrences(rule *RecurrenceRule, start time.Time, n int) []string {
+	const layout = "2006-01-02 15:04 Mon"
+	out := []string{start.Format(layout)}
+	after := start
+	for generated := 1; len(out) < n; generated++ {
+		next, ok := rule.nextAfter(start, after, generated)
+		if !ok {
+			break
+		}
+		out = append(out, next.Format(layout))
+		after = next
+	}
+	return out
+}
+
+func TestParseRecurrence(t *testing.T) {
+	loc := loadLocation("Europe/Moscow")
+	// Wednesday morning
+	start := time.Date(2025, time.January, 15, 10, 0, 0, 0, loc)
+	weekdays := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+
+	for _, tc := range []struct {
+		name     string
+		req      RecurrenceRequest
+		freq     string
+		interval int
+		byDay    []time.Weekday
+		count    int
+		until    time.Time
+	}{
+		{"daily", RecurrenceRequest{Frequency: "daily"}, "DAILY", 1, nil, 0, time.Time{}},
+		{"weekdays", RecurrenceRequest{Frequency: "Weekdays"}, "WEEKLY", 1, weekdays, 0, time.Time{}},
+		{"weekly on the start day", RecurrenceRequest{Frequency: "weekly"}, "WEEKLY", 1, []time.Weekday{time.Wednesday}, 0, time.Time{}},
+		{"weekly days are sorted from Monday", RecurrenceRequest{Frequency: "weekly", Days: []string{"su", "FR", "MO"}},
+			"WEEKLY", 1, []time.Weekday{time.Monday, time.Friday, time.Sunday}, 0, time.Time{}},
+		{"until includes the last day", RecurrenceRequest{Frequency: "daily", Interval: 2, Until: "2025-01-31"},
+			"DAILY", 2, nil, 0, time.Date(2025, time.January, 31, 23, 59, 59, 0, loc)},
+		{"count", RecurrenceRequest{Frequency: "daily", Count: 10}, "DAILY", 1, nil, 10, time.Time{}},
+
+		{"rrule", RecurrenceRequest{RRule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=WE,MO;COUNT=10"},
+			"WEEKLY", 2, []time.Weekday{time.Monday, time.Wednesday}, 10, time.Time{}},
+		{"rrule prefix and lower case", RecurrenceRequest{RRule: "RRULE:freq=daily;wkst=MO"}, "DAILY", 1, nil, 0, time.Time{}},
+		{"rrule overrides the fields", RecurrenceRequest{Frequency: "weekly", Count: 3, RRule: "FREQ=DAILY"}, "DAILY", 1, nil, 0, time.Time{}},
+		{"rrule until date", RecurrenceRequest{RRule: "FREQ=DAILY;UNTIL=20250131"},
+			"DAILY", 1, nil, 0, time.Date(2025, time.January, 31, 23, 59, 59, 0, loc)},
+		{"rrule until in UTC", RecurrenceRequest{RRule: "FREQ=DAILY;UNTIL=20250131T070000Z"},
+			"DAILY", 1, nil, 0, time.Date(2025, time.January, 31, 10, 0, 0, 0, loc)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rule, err := parseRecurrence(&tc.req, start, LocaleEn)
+			require.NoError(t, err)
+			assert.Equal(t, tc.freq, rule.Freq)
+			assert.Equal(t, tc.interval, rule.Interval)
+			assert.Equal(t, tc.byDay, rule.ByDay)
+			assert.Equal(t, tc.count, rule.Count)
+			assert.True(t, tc.until.Equal(rule.Until), "until %s, want %s", rule.Until, tc.until)
+		})
+	}
+}
+
+func TestParseRecurrenceErrors(t *testing.T) {
+	start := time.Date(2025, time.January, 15, 10, 0, 0, 0, loadLocation("Europe/Moscow"))
+
+	for _, tc := range []struct {
+		req  RecurrenceRequest
+		want string
+	}{
+		{RecurrenceRequest{Frequency: "monthly"}, tr(LocaleEn, "recurrence.unknown_frequency", "monthly")},
+		{RecurrenceRequest{Frequency: "weekly", Days: []string{"XX"}}, tr(LocaleEn, "recurrence.unknown_weekday", "XX")},
+		{RecurrenceRequest{Frequency: "daily", Until: "31.01.2025"}, tr(LocaleEn, "recurrence.invalid_until_date", "31.01.2025")},
+		{RecurrenceRequest{Frequency: "daily", Until: "2025-01-14"}, tr(LocaleEn, "recurrence.until_before_start")},
+		{RecurrenceRequest{Frequency: "daily", Interval: recurrenceMaxInterval + 1}, tr(LocaleEn, "recurrence.interval_range", recurrenceMaxInterval)},
+		{RecurrenceRequest{Frequency: "daily", Interval: -1}, tr(LocaleEn, "recurrence.interval_range", recurrenceMaxInterval)},
+		{RecurrenceRequest{Frequency: "daily", Count: recurrenceMaxCount + 1}, tr(LocaleEn, "recurrence.count_range", recurrenceMaxCount)},
+
+		{RecurrenceRequest{RRule: "FREQ=MONTHLY"}, tr(LocaleEn, "recurrence.unsupported_freq")},
+		{RecurrenceRequest{RRule: "FREQ=MONTHLY;BYMONTHDAY=15"}, tr(LocaleEn, "recurrence.unsupported_freq")},
+		{RecurrenceRequest{RRule: "FREQ=DAILY;BYMONTHDAY=15"}, tr(LocaleEn, "recurrence.unsupported_part", "BYMONTHDAY")},
+		{RecurrenceRequest{RRule: "FREQ=WEEKLY;BYDAY=MO,TU;BYSETPOS=-1"}, tr(LocaleEn, "recurrence.unsupported_part", "BYSETPOS")},
+		{RecurrenceRequest{RRule: "FREQ=WEEKLY;BYDAY=1MO"}, tr(LocaleEn, "recurrence.unsupported_byday", "1MO")},
+		{RecurrenceRequest{RRule: "FREQ=DAILY;INTERVAL=two"}, tr(LocaleEn, "recurrence.invalid_interval", "TWO")},
+		{RecurrenceRequest{RRule: "FREQ=DAILY;COUNT=x"}, tr(LocaleEn, "recurrence.invalid_count", "X")},
+		{RecurrenceRequest{RRule: "FREQ=DAILY;UNTIL=tomorrow"}, tr(LocaleEn, "recurrence.invalid_until", "TOMORROW")},
+		{RecurrenceRequest{RRule: "FREQ=DAILY;COUNT"}, tr(LocaleEn, "recurrence.invalid_part", "COUNT")},
+		{RecurrenceRequest{RRule: "INTERVAL=2"}, tr(LocaleEn, "recurrence.missing_freq")},
+	} {
+		_, err := parseRecurrence(&tc.req, start, LocaleEn)
+		if assert.Error(t, err, "%+v", tc.req) {
+			assert.Equal(t, tc.want, err.Error(), "%+v", tc.req)
+		}
+	}
+}
+
+func TestRecurrenceOccurrences(t *testing.T) {
+	loc := loadLocation("Europe/Moscow")
+	// Wednesday morning
+	start := time.Date(2025, time.January, 15, 10, 0, 0, 0, loc)
+
+	// Series are expanded up to 10 occurrences, so bounded ones show where they end
+	for _, tc := range []struct {
+		name string
+		req  RecurrenceRequest
+		want []string
+	}{
+		{"every other day", RecurrenceRequest{RRule: "FREQ=DAILY;INTERVAL=2;COUNT=4"},
+			[]string{"2025-01-15 10:00 Wed", "2025-01-17 10:00 Fri", "2025-01-19 10:00 Sun", "2025-01-21 10:00 Tue"}},
+		{"weekdays skip the weekend", RecurrenceRequest{Frequency: RecurrenceWeekdays, Count: 4},
+			[]string{"2025-01-15 10:00 Wed", "2025-01-16 10:00 Thu", "2025-01-17 10:00 Fri", "2025-01-20 10:00 Mon"}},
+		{"weekly", RecurrenceRequest{Frequency: RecurrenceWeekly, Until: "2025-02-05"},
+			[]string{"2025-01-15 10:00 Wed", "2025-01-22 10:00 Wed", "2025-01-29 10:00 Wed", "2025-02-05 10:00 Wed"}},
+		// Weeks are counted from the Monday of the first occurrence, so Monday the 13th isn't produced
+		{"every two weeks on two days", RecurrenceRequest{RRule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;UNTIL=20250210"},
+			[]string{"2025-01-15 10:00 Wed", "2025-01-27 10:00 Mon", "2025-01-29 10:00 Wed", "2025-02-10 10:00 Mon"}},
+		{"count includes the first occurrence", RecurrenceRequest{Frequency: RecurrenceDaily, Count: 3},
+			[]string{"2025-01-15 10:00 Wed", "2025-01-16 10:00 Thu", "2025-01-17 10:00 Fri"}},
+		{"single occurrence", RecurrenceRequest{RRule: "FREQ=DAILY;COUNT=1"},
+			[]string{"2025-01-15 10:00 Wed"}},
+		{"until date", RecurrenceRequest{Frequency: RecurrenceWeekly, Days: []string{"WE", "FR"}, Until: "2025-01-22"},
+			[]string{"2025-01-15 10:00 Wed", "2025-01-17 10:00 Fri", "2025-01-22 10:00 Wed"}},
+		{"until at the occurrence time", RecurrenceRequest{RRule: "FREQ=DAILY;UNTIL=20250117T070000Z"},
+			[]string{"2025-01-15 10:00 Wed", "2025-01-16 10:00 Thu", "2025-01-17 10:00 Fri"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rule, err := parseRecurrence(&tc.req, start, LocaleEn)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, occurrences(rule, start, 10))
+		})
+	}
+}
+
+func TestRecurrenceKeepsLocalTimeAcrossDST(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	// Clocks go forward on Sunday, March 30 2025
+	daily, err := parseRecurrence(&RecurrenceRequest{Frequency: RecurrenceDaily, Count: 4}, time.Date(2025, time.March, 28, 9, 0, 0, 0, loc), LocaleEn)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2025-03-28 09:00 Fri", "2025-03-29 09:00 Sat", "2025-03-30 09:00 Sun", "2025-03-31 09:00 Mon"},
+		occurrences(daily, time.Date(2025, time.March, 28, 9, 0, 0, 0, loc), 10))
+
+	// Every other week stays on the same weeks after the shift
+	start := time.Date(2025, time.March, 21, 9, 0, 0, 0, loc)
+	biweekly, err := parseRecurrence(&RecurrenceRequest{RRule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=FR;COUNT=3"}, start, LocaleEn)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2025-03-21 09:00 Fri", "2025-04-04 09:00 Fri", "2025-04-18 09:00 Fri"}, occurrences(biweekly, start, 10))
+
+	// And back on Sunday, October 26 2025
+	autumn := time.Date(2025, time.October, 25, 23, 30, 0, 0, loc)
+	nightly, err := parseRecurrence(&RecurrenceRequest{Frequency: RecurrenceDaily, Count: 3}, autumn, LocaleEn)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2025-10-25 23:30 Sat", "2025-10-26 23:30 Sun", "2025-10-27 23:30 Mon"}, occurrences(nightly, autumn, 10))
+}
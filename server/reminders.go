@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+)
+
+// Background job settings
+const (
+	ReminderCheckInterval  = time.Minute
+	reminderLockKey        = "job_lock_reminders"
+	reminderLockTTLSeconds = 50 // Shorter than the interval so the next tick can take the lock
+)
+
+// startBackgroundJobs starts the periodic job loop
+func (p *Plugin) startBackgroundJobs() {
+	p.jobsStop = make(chan struct{})
+	p.jobsDone = make(chan struct{})
+
+	go func() {
+		defer close(p.jobsDone)
+
+		ticker := time.NewTicker(ReminderCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				p.runJobOnce(reminderLockKey, reminderLockTTLSeconds, p.sendDueReminders)
+				p.runJobOnce(pendingLockKey, pendingLockTTLSeconds, p.expirePendingMeetings)
+				p.runJobOnce(seriesLockKey, seriesLockTTLSeconds, p.generateSeriesOccurrences)
+			case <-p.jobsStop:
+				return
+			}
+		}
+	}()
+}
+
+// stopBackgroundJobs stops the job loop and waits for the current run to finish
+func (p *Plugin) stopBackgroundJobs() {
+	if p.jobsStop == nil {
+		return
+	}
+	close(p.jobsStop)
+	<-p.jobsDone
+	p.jobsStop = nil
+}
+
+// runJobOnce runs the job only on the cluster node that acquires the KV lock for this tick
+func (p *Plugin) runJobOnce(lockKey string, ttlSeconds int64, job func(now time.Time)) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			p.API.LogError("[Kontur] Background job panic recovered", "job", lockKey, "error", fmt.Sprintf("%v", rec))
+		}
+	}()
+
+	acquired, appErr := p.API.KVSetWithOptions(lockKey, []byte(strconv.FormatInt(model.GetMillis(), 10)), model.PluginKVSetOptions{
+		Atomic:          true,
+		OldValue:        nil,
+		ExpireInSeconds: ttlSeconds,
+	})
+	if appErr != nil {
+		p.API.LogError("[Kontur] Failed to acquire job lock", "job", lockKey, "error", appErr.Error())
+		return
+	}
+	if !acquired {
+		// Another node handles this tick
+		return
+	}
+
+	job(time.Now())
+}
+
+// sendDueReminders DMs participants of meetings starting within the configured reminder offsets
+func (p *Plugin) sendDueReminders(now time.Time) {
+	offsets := parseReminderOffsets(p.getConfiguration().ReminderMinutes)
+
+	meetings, err := p.getUpcomingMeetings()
+	if err != nil {
+		p.API.LogError("[Kontur] Failed to load upcoming meetings", "error", err.Error())
+		return
+	}
+
+	finished := map[string][]string{} // Meeting IDs by channel
+	for _, meeting := range meetings {
+		// Drop cancelled and finished meetings from the upcoming and channel indexes
+		if meeting.IsCancelled() || meeting.HasEnded() || meeting.EndTime().Before(now) {
+			finished[meeting.ChannelID] = append(finished[meeting.ChannelID], meeting.ID)
+			continue
+		}
+		// Nothing to join yet, the provider hasn't delivered the room; or the call has already begun
+		if meeting.IsPending() || meeting.IsLive() || !meeting.StartTime().After(now) {
+			continue
+		}
+
+		if len(dueReminders(meeting, offsets, now)) == 0 {
+			continue
+		}
+
+		// Claim the reminder on the fresh record before sending, so a concurrent cancel or
+		// reschedule isn't overwritten and another node doesn't send it twice
+		claimed := false
+		fresh, err := p.modifyMeeting(meeting.ID, func(m *Meeting) bool {
+			if m.IsCancelled() || m.IsPending() || m.IsLive() || !m.StartTime().After(now) {
+				return false
+			}
+			due := dueReminders(m, offsets, now)
+			if len(due) == 0 {
+				return false
+			}
+			m.RemindersSent = append(m.RemindersSent, due...)
+			claimed = true
+			return true
+		})
+		if err != nil {
+			p.API.LogError("[Kontur] Failed to mark reminder as sent", "meeting_id", meeting.ID, "error", err.Error())
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		// If several reminders became due at once (e.g. the job was down), send only one
+		minutesLeft := int(fresh.StartTime().Sub(now).Round(time.Minute).Minutes())
+		p.sendMeetingReminder(fresh, minutesLeft)
+	}
+
+	// Channel indexes go first, so a failure leaves the meetings in the upcoming index for the next run
+	pruned := []string{}
+	for channelID, ids := range finished {
+		if err := p.removeFromIndex(kvChannelIndexPrefix+channelID, ids...); err != nil {
+			p.API.LogError("[Kontur] Failed to prune channel meetings", "channel_id", channelID, "error", err.Error())
+			continue
+		}
+		pruned = append(pruned, ids...)
+	}
+	if len(pruned) > 0 {
+		if err := p.removeFromIndex(kvUpcomingIndexKey, pruned...); err != nil {
+			p.API.LogError("[Kontur] Failed to prune upcoming meetings", "error", err.Error())
+		}
+	}
+}
+
+// dueReminders returns the reminder offsets that are due and haven't been sent yet
+func dueReminders(meeting *Meeting, offsets []int, now time.Time) []int {
+	due := []int{}
+	for _, minutes := range offsets {
+		if meeting.ReminderSent(minutes) {
+			continue
+		}
+		if !now.Before(meeting.StartTime().Add(-time.Duration(minutes) * time.Minute)) {
+			due = append(due, minutes)
+		}
+	}
+	return due
+}
+
+// sendMeetingReminder sends the reminder DM to the organizer and every participant
+func (p *Plugin) sendMeetingReminder(meeting *Meeting, minutesLeft int) {
+	channelName := ""
+	if channel, err := p.getChannelSafely(meeting.ChannelID); err == nil && channel.Type != model.ChannelTypeDirect && channel.Type != model.ChannelTypeGroup {
+		channelName = channel.Name
+	}
+
+	for _, userID := range meeting.UserIDs() {
+		// The start is shown in the recipient's own zone and language
+		loc := loadLocation(meeting.Timezone)
+		locale := DefaultLocale
+		if user, err := p.getUserSafely(userID); err == nil {
+			loc = userLocation(user)
+			locale = normalizeLocale(user.Locale)
+		}
+
+		where := ""
+		if channelName != "" {
+			where = tr(locale, "reminder.channel", channelName)
+		}
+		message := tr(locale, "reminder.message", minutesLeft, localizedMeetingTitle(meeting, locale), where) + "\n\n"
+		message += tr(locale, "reminder.start", p.formatMeetingTime(meeting.StartTime(), loc, locale)) + "\n\n"
+		message += tr(locale, "post.join_link", meeting.RoomURL)
+		if err := p.sendDirectMessage(userID, message); err != nil {
+			p.API.LogWarn("[Kontur] Failed to send reminder", "meeting_id", meeting.ID, "user_id", userID, "error", err.Error())
+		}
+	}
+
+	p.API.LogDebug("[Kontur] Reminder sent", "meeting_id", meeting.ID, "minutes_left", minutesLeft)
+}
+
+// parseReminderOffsets parses a comma separated list of minutes ("15, 1"), largest first
+func parseReminderOffsets(value string) []int {
+	offsets := []int{}
+	seen := map[string]bool{}
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" || seen[part] {
+			continue
+		}
+		minutes, err := strconv.Atoi(part)
+		if err != nil || minutes <= 0 {
+			continue
+		}
+		seen[part] = true
+		offsets = append(offsets, minutes)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(offsets)))
+	return offsets
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/mattermost/mattermost-server/v6/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newReminderTestPlugin stores a meeting starting in 10 minutes with a 15-minute reminder configured
+func newReminderTestPlugin(t *testing.T, now time.Time) (*Plugin, *plugintest.API, *memoryKV, *Meeting) {
+	p, api, kv := newTestPluginWithKV(t)
+	p.botUserID = "bot"
+	p.configuration.ReminderMinutes = "15"
+
+	meeting := &Meeting{
+		ID:              "meeting1",
+		OperationType:   OperationScheduledMeeting,
+		Status:          MeetingStatusScheduled,
+		ChannelID:       "channel",
+		OrganizerID:     "organizer",
+		StartAt:         model.GetMillisForTime(now.Add(10 * time.Minute)),
+		DurationMinutes: 30,
+		Timezone:        DefaultTimezone,
+		RoomURL:         "https://talk.example.com/room",
+	}
+	require.NoError(t, p.saveMeeting(meeting))
+	return p, api, kv, meeting
+}
+
+func TestSendDueRemindersRecordsReminderOnce(t *testing.T) {
+	now := time.Now()
+	p, api, _, meeting := newReminderTestPlugin(t, now)
+	api.On("GetChannel", "channel").Return(&model.Channel{Id: "channel", Name: "town-square", Type: model.ChannelTypeOpen}, nil)
+	api.On("GetUser", "organizer").Return(&model.User{Id: "organizer", Locale: "ru"}, nil)
+	api.On("GetDirectChannel", "bot", "organizer").Return(&model.Channel{Id: "dm"}, nil)
+	api.On("CreatePost", mock.MatchedBy(func(post *model.Post) bool { return post.ChannelId == "dm" })).Return(&model.Post{}, nil).Once()
+
+	p.sendDueReminders(now)
+	p.sendDueReminders(now.Add(time.Minute))
+
+	stored, err := p.getMeeting(meeting.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []int{15}, stored.RemindersSent)
+	api.AssertNumberOfCalls(t, "CreatePost", 1)
+}
+
+func TestSendDueRemindersSkipsMeetingCancelledMeanwhile(t *testing.T) {
+	now := time.Now()
+	p, _, kv, meeting := newReminderTestPlugin(t, now)
+
+	// The meeting is cancelled right after the job has loaded the upcoming index
+	cancelled := *meeting
+	cancelled.Status = MeetingStatusCancelled
+	onFirstMeetingRead(t, p, kv, &cancelled)
+
+	p.sendDueReminders(now)
+
+	stored, err := p.getMeeting(meeting.ID)
+	require.NoError(t, err)
+	assert.Equal(t, MeetingStatusCancelled, stored.Status)
+	assert.Empty(t, stored.RemindersSent)
+}
+
+func TestSendDueRemindersPrunesChannelIndex(t *testing.T) {
+	now := time.Now()
+	p, _ := newTestPlugin(t)
+	p.configuration.ReminderMinutes = "15"
+
+	for _, meeting := range []*Meeting{
+		{ID: "finished", StartAt: model.GetMillisForTime(now.Add(-2 * time.Hour))},
+		{ID: "cancelled", Status: MeetingStatusCancelled, StartAt: model.GetMillisForTime(now.Add(time.Hour))},
+		{ID: "upcoming", StartAt: model.GetMillisForTime(now.Add(2 * time.Hour))},
+		{ID: "call", OperationType: OperationInstantCall, StartAt: model.GetMillisForTime(now)},
+	} {
+		if meeting.OperationType == "" {
+			meeting.OperationType = OperationScheduledMeeting
+		}
+		if meeting.Status == "" {
+			meeting.Status = MeetingStatusScheduled
+		}
+		meeting.ChannelID, meeting.OrganizerID, meeting.DurationMinutes = "channel", "organizer", 30
+		require.NoError(t, p.saveMeeting(meeting))
+	}
+
+	// Instant calls are never listed, so they don't enter the channel index
+	channelIDs, _, err := p.getIndex(kvChannelIndexPrefix + "channel")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"finished", "cancelled", "upcoming"}, channelIDs)
+
+	p.sendDueReminders(now)
+
+	channelIDs, _, err = p.getIndex(kvChannelIndexPrefix + "channel")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"upcoming"}, channelIDs)
+	upcomingIDs, _, err := p.getIndex(kvUpcomingIndexKey)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"upcoming"}, upcomingIDs)
+	// The user index keeps the whole history
+	userIDs, _, err := p.getIndex(kvUserIndexPrefix + "organizer")
+	require.NoError(t, err)
+	assert.Len(t, userIDs, 4)
+}
+
+func TestSendMeetingReminderUsesRecipientLocale(t *testing.T) {
+	now := time.Now()
+	p, api, _, meeting := newReminderTestPlugin(t, now)
+	meeting.ParticipantIDs = []string{"participant"}
+	api.On("GetChannel", "channel").Return(&model.Channel{Id: "channel", Name: "town-square", Type: model.ChannelTypeOpen}, nil)
+	api.On("GetUser", "organizer").Return(&model.User{Id: "organizer", Locale: "ru"}, nil)
+	api.On("GetUser", "participant").Return(&model.User{Id: "participant", Locale: "en"}, nil)
+	api.On("GetDirectChannel", "bot", "organizer").Return(&model.Channel{Id: "dm-organizer"}, nil)
+	api.On("GetDirectChannel", "bot", "participant").Return(&model.Channel{Id: "dm-participant"}, nil)
+	sent := map[string]string{}
+	api.On("CreatePost", mock.Anything).Run(func(args mock.Arguments) {
+		post := args.Get(0).(*model.Post)
+		sent[post.ChannelId] = post.Message
+	}).Return(&model.Post{}, nil)
+
+	p.sendMeetingReminder(meeting, 10)
+
+	assert.Contains(t, sent["dm-organizer"], "⏰ Через 10 мин начнётся «Встреча» в ~town-square")
+	assert.Contains(t, sent["dm-participant"], "⏰ “Meeting” starts in 10 min in ~town-square")
+	assert.Contains(t, sent["dm-participant"], meeting.RoomURL)
+}
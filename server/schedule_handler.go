@@ -2,10 +2,13 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/mattermost/mattermost-server/v6/model"
@@ -20,6 +23,9 @@ type WebhookError struct {
 
 // Error implements the error interface
 func (e *WebhookError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("webhook returned error (status %d)", e.StatusCode)
+	}
 	return e.Message
 }
 
@@ -29,6 +35,19 @@ func IsWebhookError(err error) (*WebhookError, bool) {
 	return webhookErr, ok
 }
 
+// RequestError is a failed pipeline step, ready to be returned to the client
+type RequestError struct {
+	StatusCode int
+	Field      string
+	Message    string
+	Conflicts  []*MeetingConflict // Set for 409 responses about busy participants
+}
+
+// Error implements the error interface
+func (e *RequestError) Error() string {
+	return e.Message
+}
+
 // ScheduleRequest represents the schedule meeting request
 type ScheduleRequest struct {
 	ChannelID              string   `json:"channel_id"`
@@ -44,6 +63,9 @@ type ScheduleRequest struct {
 	CreateGoogleCalendarEvent bool   `json:"create_google_calendar_event"`
 	ServiceName            string   `json:"service_name"`
 	RootID                 string   `json:"root_id"` // ID родительского сообщения для создания поста в треде
+	RequestID              string   `json:"request_id"` // Ключ идемпотентности: повторная отправка формы не создаст вторую комнату
+	Recurrence             *RecurrenceRequest `json:"recurrence"` // Повторение встречи; первая встреча серии — start_at/start_at_local
+	Force                  bool     `json:"force"` // Создать встречу, даже если участники заняты в это время
 	// Новые поля с правильной обработкой таймзон
 	StartTimeClient        string   `json:"start_time_client"`
 	EndTimeClient          string   `json:"end_time_client"`
@@ -51,15 +73,23 @@ type ScheduleRequest struct {
 	EndTimeUTC             string   `json:"end_time_utc"`
 	StartTimeMSK           string   `json:"start_time_msk"`
 	EndTimeMSK             string   `json:"end_time_msk"`
+	// Тип операции для вебхука (не приходит от клиента)
+	OperationType          string   `json:"-"`
+	// Серия, к которой относится встреча (не приходит от клиента)
+	series                 *Series
+	// Язык сообщений действующего пользователя (не приходит от клиента)
+	locale                 string
+	// ID, зарезервированный для встречи серии (не приходит от клиента)
+	meetingID              string
 }
 
 // validateScheduleRequest validates and parses the incoming request
-func (p *Plugin) validateScheduleRequest(w http.ResponseWriter, r *http.Request) (*ScheduleRequest, bool) {
+func (p *Plugin) validateScheduleRequest(w http.ResponseWriter, r *http.Request, userID, locale string) (*ScheduleRequest, bool) {
 	// Read request body
 	bodyBytes, err := io.ReadAll(r.Body)
 	if err != nil {
 		p.API.LogError("[Kontur] Failed to read request body", "error", err.Error())
-		writeErrorResponse(w, http.StatusBadRequest, "general", "Не удалось прочитать запрос")
+		writeErrorResponse(w, http.StatusBadRequest, "general", tr(locale, "request.read_failed"))
 		return nil, false
 	}
 	r.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
@@ -68,9 +98,19 @@ func (p *Plugin) validateScheduleRequest(w http.ResponseWriter, r *http.Request)
 	var req ScheduleRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		p.API.LogError("[Kontur] Failed to parse JSON", "error", err.Error())
-		writeErrorResponse(w, http.StatusBadRequest, "general", "Неверный формат JSON: "+err.Error())
+		writeErrorResponse(w, http.StatusBadRequest, "general", tr(locale, "request.invalid_json", err.Error()))
+		return nil, false
+	}
+
+	// The acting user is the authenticated one; body user_id may only repeat it
+	actingUserID, err := resolveActingUserID(userID, req.UserID)
+	if err != nil {
+		p.API.LogWarn("[Kontur] Schedule request user mismatch", "error", err.Error())
+		writeErrorResponse(w, http.StatusForbidden, RequestFieldUserID, tr(locale, "request.user_mismatch"))
 		return nil, false
 	}
+	req.UserID = actingUserID
+	req.locale = locale
 
 	// Log only safe metadata (no PII: emails, names, participant details)
 	p.API.LogInfo("[Kontur] Schedule request received",
@@ -93,20 +133,28 @@ func (p *Plugin) validateScheduleRequest(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Validate required fields
+	errors := validateScheduleFields(&req)
+
+	if len(errors) > 0 {
+		p.API.LogError("[Kontur] Validation failed", "error_count", len(errors))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		response := map[string]interface{}{"errors": errors}
+		json.NewEncoder(w).Encode(response)
+		return nil, false
+	}
+
+	return &req, true
+}
+
+// validateScheduleFields checks required fields, duration and title of a schedule request
+func validateScheduleFields(req *ScheduleRequest) []map[string]string {
 	errors := []map[string]string{}
 
 	if req.ChannelID == "" {
 		errors = append(errors, map[string]string{
 			"field":   RequestFieldChannelID,
-			"message": "channel_id обязателен",
-		})
-	}
-
-	if req.UserID == "" {
-		p.API.LogError("[Kontur] user_id is empty")
-		errors = append(errors, map[string]string{
-			"field":   RequestFieldUserID,
-			"message": "user_id обязателен",
+			"message": tr(req.locale, "validation.channel_required"),
 		})
 	}
 
@@ -114,12 +162,12 @@ func (p *Plugin) validateScheduleRequest(w http.ResponseWriter, r *http.Request)
 	if req.DurationMinutes < 5 {
 		errors = append(errors, map[string]string{
 			"field":   "duration_minutes",
-			"message": "Продолжительность должна быть не менее 5 минут",
+			"message": tr(req.locale, "validation.duration_too_short", 5),
 		})
 	} else if req.DurationMinutes > 480 {
 		errors = append(errors, map[string]string{
 			"field":   "duration_minutes",
-			"message": "Продолжительность не может превышать 480 минут (8 часов)",
+			"message": tr(req.locale, "validation.duration_too_long", 480, 8),
 		})
 	}
 
@@ -127,20 +175,11 @@ func (p *Plugin) validateScheduleRequest(w http.ResponseWriter, r *http.Request)
 	if req.Title != nil && len(*req.Title) > 100 {
 		errors = append(errors, map[string]string{
 			"field":   "title",
-			"message": "Название не может быть длиннее 100 символов",
+			"message": tr(req.locale, "validation.title_too_long", 100),
 		})
 	}
 
-	if len(errors) > 0 {
-		p.API.LogError("[Kontur] Validation failed", "error_count", len(errors))
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		response := map[string]interface{}{"errors": errors}
-		json.NewEncoder(w).Encode(response)
-		return nil, false
-	}
-
-	return &req, true
+	return errors
 }
 
 // parseDateTime parses and validates date/time from request
@@ -167,8 +206,16 @@ func (p *Plugin) parseDateTime(req *ScheduleRequest) (time.Time, error) {
 			}
 		}
 
+		// Fall back to natural language ("завтра в 15:00", "next monday 10:00") in the user's timezone
+		if err != nil {
+			scheduledAt, err = parseNaturalDateTime(req.StartAtLocal, time.Now(), loadLocation(req.Timezone))
+			if err == nil {
+				p.API.LogDebug("[Kontur] Parsed start_at_local as natural language", "input", req.StartAtLocal, "parsed", scheduledAt.Format(time.RFC3339))
+			}
+		}
+
 		if err != nil {
-			return time.Time{}, fmt.Errorf("неверный формат локального времени: %s", req.StartAtLocal)
+			return time.Time{}, fmt.Errorf("%s", tr(req.locale, "datetime.invalid_local", req.StartAtLocal))
 		}
 	} else if req.StartAt != "" {
 		// Fallback to UTC
@@ -188,10 +235,10 @@ func (p *Plugin) parseDateTime(req *ScheduleRequest) (time.Time, error) {
 		}
 
 		if err != nil {
-			return time.Time{}, fmt.Errorf("неверный формат даты и времени: %s", req.StartAt)
+			return time.Time{}, fmt.Errorf("%s", tr(req.locale, "datetime.invalid", req.StartAt))
 		}
 	} else {
-		return time.Time{}, fmt.Errorf("дата и время обязательны")
+		return time.Time{}, fmt.Errorf("%s", tr(req.locale, "datetime.required"))
 	}
 
 	// Validate time range
@@ -199,10 +246,10 @@ func (p *Plugin) parseDateTime(req *ScheduleRequest) (time.Time, error) {
 	maxDate := now.Add(30 * 24 * time.Hour)
 
 	if scheduledAt.Before(now) {
-		return time.Time{}, fmt.Errorf("дата и время не могут быть в прошлом")
+		return time.Time{}, fmt.Errorf("%s", tr(req.locale, "datetime.in_past"))
 	}
 	if scheduledAt.After(maxDate) {
-		return time.Time{}, fmt.Errorf("дата не может быть более чем через 30 дней")
+		return time.Time{}, fmt.Errorf("%s", tr(req.locale, "datetime.too_far", 30))
 	}
 
 	return scheduledAt, nil
@@ -225,6 +272,165 @@ func (p *Plugin) getUserAndChannel(req *ScheduleRequest) (*model.User, *model.Ch
 	return currentUser, channel, nil
 }
 
+// getUserAndChannelForRequest wraps getUserAndChannel with client-facing errors
+func (p *Plugin) getUserAndChannelForRequest(req *ScheduleRequest) (*model.User, *model.Channel, *RequestError) {
+	currentUser, channel, err := p.getUserAndChannel(req)
+	if err != nil {
+		p.API.LogError("[Kontur] Failed to get user/channel", "error", err.Error())
+		if currentUser == nil {
+			return nil, nil, &RequestError{StatusCode: http.StatusNotFound, Field: RequestFieldUserID,
+				Message: tr(req.locale, "schedule.user_not_found", req.UserID)}
+		}
+		return nil, nil, &RequestError{StatusCode: http.StatusNotFound, Field: RequestFieldChannelID,
+			Message: tr(req.locale, "schedule.channel_not_found", req.ChannelID)}
+	}
+	return currentUser, channel, nil
+}
+
+// scheduleMeeting runs a validated schedule request through date parsing, access checks,
+// the webhook and post creation. Shared by the HTTP endpoint and the slash command.
+func (p *Plugin) scheduleMeeting(req *ScheduleRequest) (*Meeting, *RequestError) {
+	// Times without an offset are read in the organizer's zone from the Mattermost profile,
+	// messages use the organizer's language
+	if req.Timezone == "" || req.locale == "" {
+		if organizer, err := p.getUserSafely(req.UserID); err == nil {
+			if req.Timezone == "" {
+				req.Timezone = organizer.GetPreferredTimezone()
+			}
+			if req.locale == "" {
+				req.locale = normalizeLocale(organizer.Locale)
+			}
+		}
+	}
+
+	// Parse and validate date/time
+	scheduledAt, err := p.parseDateTime(req)
+	if err != nil {
+		p.API.LogError("[Kontur] Date/time validation failed", "error", err.Error())
+		return nil, &RequestError{StatusCode: http.StatusBadRequest, Field: RequestFieldStartAtLocal, Message: err.Error()}
+	}
+
+	// Get user and channel
+	currentUser, channel, reqErr := p.getUserAndChannelForRequest(req)
+	if reqErr != nil {
+		return nil, reqErr
+	}
+
+	// Check that the user may post in the channel
+	if err := p.checkChannelAccess(currentUser.Id, channel, req.locale); err != nil {
+		return nil, &RequestError{StatusCode: http.StatusForbidden, Field: RequestFieldChannelID, Message: err.Error()}
+	}
+
+	// Resolve participants
+	participants, err := p.resolveParticipants(req, channel)
+	if err != nil {
+		p.API.LogError("[Kontur] Failed to resolve participants", "error", err.Error())
+		return nil, &RequestError{StatusCode: http.StatusBadRequest, Field: RequestFieldParticipantIDs, Message: err.Error()}
+	}
+
+	// Restrict participants to channel/team members if configured
+	if err := p.checkParticipantsAccess(participants, channel, req.TeamID, req.locale); err != nil {
+		return nil, &RequestError{StatusCode: http.StatusForbidden, Field: RequestFieldParticipantIDs, Message: err.Error()}
+	}
+
+	// Refuse to double-book the organizer or participants unless the client confirmed it
+	if !req.Force {
+		endAt := scheduledAt.Add(time.Duration(req.DurationMinutes) * time.Minute)
+		if reqErr := p.checkConflicts(currentUser.Id, meetingUsers(currentUser, participants), scheduledAt, endAt, "", loadLocation(req.Timezone), req.locale); reqErr != nil {
+			return nil, reqErr
+		}
+	}
+
+	// Recurring meetings: the request creates the first occurrence, the series produces the rest
+	if req.Recurrence != nil {
+		if reqErr := p.prepareSeries(req, currentUser, channel, participants, scheduledAt); reqErr != nil {
+			return nil, reqErr
+		}
+	}
+
+	meeting, reqErr := p.createMeeting(req, currentUser, channel, participants, scheduledAt)
+	if reqErr != nil {
+		return nil, reqErr
+	}
+	if req.series != nil {
+		p.startSeries(req.series, meeting)
+	}
+	p.sendLocalTimeNotices(meeting, false)
+	return meeting, nil
+}
+
+// createMeeting creates the room at the provider, announces the meeting in the channel and stores it
+func (p *Plugin) createMeeting(req *ScheduleRequest, currentUser *model.User, channel *model.Channel, participants []*model.User, scheduledAt time.Time) (*Meeting, *RequestError) {
+	provider, reqErr := p.getMeetingProvider(req.locale)
+	if reqErr != nil {
+		return nil, reqErr
+	}
+
+	// Create the room at the provider
+	meetingID := req.meetingID
+	if meetingID == "" {
+		meetingID = model.NewId()
+	}
+	meeting := newMeeting(meetingID, req, currentUser, channel, participants, scheduledAt)
+	room, err := provider.CreateMeeting(&ProviderRequest{
+		Meeting:      meeting,
+		UserID:       currentUser.Id,
+		Locale:       req.locale,
+		Schedule:     req,
+		Organizer:    currentUser,
+		Channel:      channel,
+		Participants: participants,
+	})
+	if err != nil {
+		return nil, providerRequestError(err, req.locale, "provider.create_failed")
+	}
+
+	meeting.RoomURL = room.URL
+	meeting.RoomID = room.ID
+	if room.Pending {
+		meeting.Status = MeetingStatusPending
+	}
+
+	// Remember the meeting before announcing it, so a retried series occurrence finds it instead of posting again
+	if err := p.saveMeeting(meeting); err != nil {
+		p.API.LogError("[Kontur] Failed to save meeting", "meeting_id", meeting.ID, "error", err.Error())
+		return nil, &RequestError{StatusCode: http.StatusInternalServerError, Field: RequestFieldGeneral,
+			Message: tr(req.locale, "schedule.save_failed")}
+	}
+
+	// Create post in channel or thread
+	post, err := p.createPost(channel, currentUser, participants, meeting)
+	if err != nil {
+		// Don't fail the request if post creation fails (meeting is already created)
+		p.API.LogWarn("[Kontur] Failed to create post, but meeting was created", "error", err.Error())
+		return meeting, nil
+	}
+	p.setMeetingPost(meeting, post.Id)
+
+	return meeting, nil
+}
+
+// setMeetingPost records the announcement of a stored meeting and refreshes it if the room arrived meanwhile
+func (p *Plugin) setMeetingPost(meeting *Meeting, postID string) {
+	saved, err := p.modifyMeeting(meeting.ID, func(m *Meeting) bool {
+		m.PostID = postID
+		return true
+	})
+	if err != nil {
+		p.API.LogError("[Kontur] Failed to save meeting post", "meeting_id", meeting.ID, "error", err.Error())
+		meeting.PostID = postID
+		return
+	}
+
+	statusChanged := saved.Status != meeting.Status
+	*meeting = *saved
+	if statusChanged {
+		if err := p.updateMeetingPost(meeting); err != nil {
+			p.API.LogWarn("[Kontur] Failed to update meeting post", "meeting_id", meeting.ID, "error", err.Error())
+		}
+	}
+}
+
 // resolveParticipants resolves participant IDs to user objects
 func (p *Plugin) resolveParticipants(req *ScheduleRequest, channel *model.Channel) ([]*model.User, error) {
 	// Auto-add other user for DM channels
@@ -252,7 +458,7 @@ func (p *Plugin) resolveParticipants(req *ScheduleRequest, channel *model.Channe
 
 	// Validate participants
 	if len(req.ParticipantIDs) == 0 && channel.Type != model.ChannelTypeDirect {
-		return nil, fmt.Errorf("необходимо выбрать хотя бы одного участника")
+		return nil, fmt.Errorf("%s", tr(req.locale, "schedule.participants_required"))
 	}
 
 	// Get participant info
@@ -267,7 +473,7 @@ func (p *Plugin) resolveParticipants(req *ScheduleRequest, channel *model.Channe
 	}
 
 	if len(participants) == 0 {
-		return nil, fmt.Errorf("не удалось получить информацию об участниках")
+		return nil, fmt.Errorf("%s", tr(req.locale, "schedule.participants_unavailable"))
 	}
 
 	p.API.LogDebug("[Kontur] Participants loaded", "count", len(participants))
@@ -347,8 +553,13 @@ func (p *Plugin) buildWebhookPayload(req *ScheduleRequest, currentUser *model.Us
 		p.API.LogDebug("[Kontur] Using computed time fields (legacy mode)")
 	}
 
+	operationType := req.OperationType
+	if operationType == "" {
+		operationType = OperationScheduledMeeting
+	}
+
 	payload := map[string]interface{}{
-		"operation_type":     "scheduled_meeting",
+		"operation_type":     operationType,
 		"service_name":       serviceName,
 		// Новые поля (приоритет)
 		"start_time_client":  startTimeClientStr,
@@ -376,34 +587,164 @@ func (p *Plugin) buildWebhookPayload(req *ScheduleRequest, currentUser *model.Us
 		"root_id":                    req.RootID,
 		"is_thread_reply":            req.RootID != "",
 	}
+	if req.RequestID != "" {
+		payload["request_id"] = req.RequestID
+	}
+
+	// Occurrences of a series carry its room so the webhook can reuse it instead of creating a new one
+	if req.series != nil {
+		payload["series_id"] = req.series.ID
+		payload["recurrence"] = req.series.RRule
+		if req.series.RoomURL != "" {
+			payload["room_url"] = req.series.RoomURL
+			payload["room_id"] = req.series.RoomID
+		}
+	}
+
+	return payload
+}
+
+// buildMeetingWebhookPayload creates the payload for operations on an existing meeting
+func (p *Plugin) buildMeetingWebhookPayload(operationType string, meeting *Meeting, userID string) map[string]interface{} {
+	serviceName := ""
+	if config := p.getConfiguration(); config != nil {
+		serviceName = config.ServiceName
+	}
 
+	payload := map[string]interface{}{
+		"operation_type":   operationType,
+		"service_name":     serviceName,
+		"meeting_id":       meeting.ID,
+		"room_id":          meeting.RoomID,
+		"room_url":         meeting.RoomURL,
+		"title":            meeting.Title,
+		"channel_id":       meeting.ChannelID,
+		"organizer_id":     meeting.OrganizerID,
+		"participant_ids":  meeting.ParticipantIDs,
+		"start_time_utc":   meeting.StartTime().UTC().Format(time.RFC3339),
+		"end_time_utc":     meeting.EndTime().UTC().Format(time.RFC3339),
+		"start_time_msk":   convertToMSK(meeting.StartTime()),
+		"end_time_msk":     convertToMSK(meeting.EndTime()),
+		"timezone":         meeting.Timezone,
+		"duration_minutes": meeting.DurationMinutes,
+		"user_id":          userID,
+		"timestamp":        time.Now().Format(time.RFC3339),
+	}
+	if meeting.SeriesID != "" {
+		payload["series_id"] = meeting.SeriesID
+	}
 	return payload
 }
 
-// sendWebhook sends the webhook request
+// sendWebhook sends the webhook request, retrying network errors and 5xx responses with exponential backoff.
+// All attempts share one request_id, also sent as the Idempotency-Key header, so the webhook can de-duplicate.
 func (p *Plugin) sendWebhook(webhookURL string, payload map[string]interface{}) (map[string]interface{}, error) {
+	maxRetries := p.getConfiguration().WebhookMaxRetries
+	if maxRetries < 0 {
+		maxRetries = 0
+	} else if maxRetries > WebhookMaxRetriesLimit {
+		maxRetries = WebhookMaxRetriesLimit
+	}
+	return p.sendWebhookWithin(webhookURL, payload, WebhookTimeout, maxRetries)
+}
+
+// sendWebhookWithin is sendWebhook with an explicit number of retries; timeout bounds all attempts together
+func (p *Plugin) sendWebhookWithin(webhookURL string, payload map[string]interface{}, timeout time.Duration, maxRetries int) (map[string]interface{}, error) {
+	requestID, _ := payload["request_id"].(string)
+	if requestID == "" {
+		requestID = model.NewId()
+		payload["request_id"] = requestID
+	}
+
 	payloadJSON, err := json.Marshal(payload)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
-	p.API.LogDebug("[Kontur] Sending webhook", "url", webhookURL, "payload_size", len(payloadJSON))
+	// One deadline covers all attempts and the pauses between them
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	client := &http.Client{}
+
+	for attempt := 0; ; attempt++ {
+		p.API.LogDebug("[Kontur] Sending webhook", "url", webhookURL, "payload_size", len(payloadJSON),
+			"request_id", requestID, "attempt", attempt+1)
+
+		httpReq, err := p.newWebhookRequest(ctx, webhookURL, payloadJSON, requestID)
+		if err != nil {
+			// A malformed URL won't get better on retry
+			return nil, err
+		}
 
-	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: WebhookTimeout,
+		webhookData, statusCode, err := p.sendWebhookOnce(client, httpReq)
+		// Only failures that may be transient are retried: no response at all or a 5xx
+		retryable := err != nil && (statusCode == 0 || statusCode >= http.StatusInternalServerError)
+		if !retryable || attempt >= maxRetries || ctx.Err() != nil {
+			return webhookData, err
+		}
+
+		delay := webhookRetryDelay(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= delay {
+			// The next attempt would start past the deadline
+			return nil, err
+		}
+		p.API.LogWarn("[Kontur] Webhook request failed, retrying",
+			"request_id", requestID,
+			"attempt", attempt+1,
+			"status_code", statusCode,
+			"retry_in", delay.String(),
+			"error", err.Error())
+		time.Sleep(delay)
 	}
+}
+
+// webhookJitter is the random source shared by all webhook retries; rand.Rand isn't safe for concurrent use
+var (
+	webhookJitterMu sync.Mutex
+	webhookJitter   = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// webhookRetryDelay returns the backoff before the next attempt: exponential with full jitter in [d/2, d]
+func webhookRetryDelay(attempt int) time.Duration {
+	delay := WebhookRetryBaseDelay << uint(attempt)
+	if delay <= 0 || delay > WebhookRetryMaxDelay {
+		delay = WebhookRetryMaxDelay
+	}
+	webhookJitterMu.Lock()
+	defer webhookJitterMu.Unlock()
+	return delay/2 + time.Duration(webhookJitter.Int63n(int64(delay/2)+1))
+}
+
+// newWebhookRequest builds one webhook attempt
+func (p *Plugin) newWebhookRequest(ctx context.Context, webhookURL string, payloadJSON []byte, requestID string) (*http.Request, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(payloadJSON))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set(HeaderIdempotencyKey, requestID)
+
+	// Sign every attempt with a fresh timestamp so the webhook can verify the sender and reject replays
+	if secret := p.getConfiguration().WebhookSecret; secret != "" {
+		timestamp := signatureTimestamp(time.Now())
+		httpReq.Header.Set(HeaderKonturTimestamp, timestamp)
+		httpReq.Header.Set(HeaderKonturSignature, signPayload(secret, timestamp, payloadJSON))
+	}
+	return httpReq, nil
+}
 
-	resp, err := client.Post(webhookURL, "application/json", bytes.NewBuffer(payloadJSON))
+// sendWebhookOnce makes a single webhook request. The status code is 0 when no response was received.
+func (p *Plugin) sendWebhookOnce(client *http.Client, httpReq *http.Request) (map[string]interface{}, int, error) {
+	resp, err := client.Do(httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return nil, 0, fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Read response
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, 0, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	p.API.LogDebug("[Kontur] Webhook response", "status", resp.StatusCode, "body", string(bodyBytes))
@@ -412,12 +753,12 @@ func (p *Plugin) sendWebhook(webhookURL string, payload map[string]interface{})
 	var webhookData map[string]interface{}
 	if len(bodyBytes) > 0 {
 		if err := json.Unmarshal(bodyBytes, &webhookData); err != nil {
-			return nil, fmt.Errorf("failed to parse response (status %d): %w", resp.StatusCode, err)
+			return nil, resp.StatusCode, fmt.Errorf("failed to parse response (status %d): %w", resp.StatusCode, err)
 		}
 	} else {
 		// Empty body is an error - webhook should return JSON
 		if resp.StatusCode == http.StatusOK {
-			return nil, fmt.Errorf("webhook returned empty response (status %d)", resp.StatusCode)
+			return nil, resp.StatusCode, fmt.Errorf("webhook returned empty response (status %d)", resp.StatusCode)
 		}
 		webhookData = make(map[string]interface{})
 	}
@@ -431,10 +772,9 @@ func (p *Plugin) sendWebhook(webhookURL string, payload map[string]interface{})
 			}
 
 			// Extract message
+			// Without a message the client gets a generic text in its own language
 			if msg, ok := webhookData["message"].(string); ok && msg != "" {
 				webhookErr.Message = msg
-			} else {
-				webhookErr.Message = fmt.Sprintf("Ошибка при создании встречи (статус %d)", resp.StatusCode)
 			}
 
 			// Extract execution_id
@@ -442,7 +782,7 @@ func (p *Plugin) sendWebhook(webhookURL string, payload map[string]interface{})
 				webhookErr.ExecutionID = execID
 			}
 
-			return nil, webhookErr
+			return nil, resp.StatusCode, webhookErr
 		}
 
 		// Fallback to legacy error format
@@ -452,7 +792,7 @@ func (p *Plugin) sendWebhook(webhookURL string, payload map[string]interface{})
 		} else if errMsg, ok := webhookData["error"].(string); ok && errMsg != "" {
 			errorMsg = errMsg
 		}
-		return nil, fmt.Errorf("%s", errorMsg)
+		return nil, resp.StatusCode, fmt.Errorf("%s", errorMsg)
 	}
 
 	// Check success flag
@@ -470,67 +810,97 @@ func (p *Plugin) sendWebhook(webhookURL string, payload map[string]interface{})
 		}
 
 		if !success {
-			errorMsg := "Не удалось создать встречу"
+			errorMsg := "webhook reported failure"
 			if msg, ok := webhookData["message"].(string); ok && msg != "" {
 				errorMsg = msg
 			}
-			return nil, fmt.Errorf("%s", errorMsg)
+			return nil, resp.StatusCode, fmt.Errorf("%s", errorMsg)
 		}
 	}
 
-	return webhookData, nil
+	return webhookData, resp.StatusCode, nil
 }
 
-// createPost creates a post in the channel or thread
-func (p *Plugin) createPost(channel *model.Channel, currentUser *model.User, participants []*model.User, scheduledAt time.Time, duration int, roomURL string, rootID string, req *ScheduleRequest) error {
-	// Format participants list
-	participantsList := ""
-	for i, user := range participants {
-		if i > 0 {
-			participantsList += ", "
+// webhookRequestError converts a sendWebhook failure into a client error prefixed with the failureKey message
+func (p *Plugin) webhookRequestError(err error, webhookURL, locale, failureKey string) *RequestError {
+	// Check if this is a structured n8n error
+	if webhookErr, ok := IsWebhookError(err); ok {
+		// Log with execution_id for debugging
+		if webhookErr.ExecutionID != "" {
+			p.API.LogError("[Kontur] Webhook returned error from n8n",
+				"error", webhookErr.Message,
+				"execution_id", webhookErr.ExecutionID,
+				"status_code", webhookErr.StatusCode)
+		} else {
+			p.API.LogError("[Kontur] Webhook returned error from n8n",
+				"error", webhookErr.Message,
+				"status_code", webhookErr.StatusCode)
 		}
-		participantsList += "@" + user.Username
-	}
 
-	// Format scheduled time in MSK
-	var scheduledAtFormatted string
+		// Return n8n error message to user
+		// Use the status code from n8n, but default to 400 if it's not a client error
+		statusCode := webhookErr.StatusCode
+		if statusCode < 400 || statusCode >= 500 {
+			statusCode = http.StatusBadRequest
+		}
 
-	// Primary source: new start_time_msk field from request (already calculated on frontend)
-	if req != nil && req.StartTimeMSK != "" {
-		if mskTime, err := time.Parse(time.RFC3339, req.StartTimeMSK); err == nil {
-			scheduledAtFormatted = mskTime.Format("02.01.2006, 15:04") + " (по МСК)"
-		} else {
-			p.API.LogWarn("[Kontur] Failed to parse start_time_msk, falling back to legacy time",
-				"start_time_msk", req.StartTimeMSK, "error", err.Error())
+		message := webhookErr.Message
+		if message == "" {
+			message = tr(locale, "webhook.error_status", webhookErr.StatusCode)
 		}
+		return &RequestError{StatusCode: statusCode, Field: RequestFieldGeneral, Message: message}
 	}
 
-	// Fallback for legacy clients: convert scheduledAt to MSK before formatting
-	if scheduledAtFormatted == "" {
-		mskLocation, err := time.LoadLocation("Europe/Moscow")
-		if err != nil {
-			// Fallback to UTC+3 if location loading fails
-			mskLocation = time.FixedZone("MSK", 3*60*60)
-		}
-		scheduledAtMSK := scheduledAt.In(mskLocation)
-		scheduledAtFormatted = scheduledAtMSK.Format("02.01.2006, 15:04") + " (по МСК)"
+	// Network or other non-n8n errors
+	p.API.LogError("[Kontur] Webhook request failed", "url", webhookURL, "error", err.Error())
+
+	// Detailed error message for webhook failures (the URL itself is not exposed to clients)
+	errorMsg := tr(locale, failureKey) + ".\n\n" + tr(locale, "webhook.unreachable")
+
+	return &RequestError{StatusCode: http.StatusInternalServerError, Field: RequestFieldGeneral, Message: errorMsg}
+}
+
+// extractRoomID returns the provider room identifier from a webhook response, if any
+func extractRoomID(webhookData map[string]interface{}) string {
+	if id, ok := webhookData[WebhookFieldRoomID].(string); ok {
+		return id
 	}
+	return ""
+}
 
-	// Create message
-	postMessage := fmt.Sprintf("📅 @%s запланировал встречу на %s\n\n", currentUser.Username, scheduledAtFormatted)
-	postMessage += fmt.Sprintf("👥 Участники: %s\n\n", participantsList)
-	postMessage += fmt.Sprintf("⏱ Длительность: %d минут\n\n", duration)
-	if roomURL != "" {
-		postMessage += fmt.Sprintf("[🔗 Присоединиться к встрече](%s)", roomURL)
+// extractRoomURL returns the room link from a webhook response (room_url or meeting_url)
+func extractRoomURL(webhookData map[string]interface{}) string {
+	if url, ok := webhookData[WebhookFieldRoomURL].(string); ok && url != "" {
+		return url
 	}
+	if url, ok := webhookData[WebhookFieldMeetingURL].(string); ok {
+		return url
+	}
+	return ""
+}
+
+// createPost creates a post in the channel or thread
+func (p *Plugin) createPost(channel *model.Channel, currentUser *model.User, participants []*model.User, meeting *Meeting) (*model.Post, error) {
+	// Announcements go either from the organizer or from the bot with attribution
+	postAsBot := p.shouldPostAsBot()
 
 	post := &model.Post{
 		ChannelId: channel.Id,
-		Message:   postMessage,
 		UserId:    currentUser.Id,
 	}
+	if postAsBot {
+		post.UserId = p.botUserID
+		post.AddProp("organizer_id", currentUser.Id)
+	}
+
+	if meeting.OperationType == OperationInstantCall {
+		p.renderInstantCallPost(post, meeting, currentUser)
+	} else {
+		p.renderMeetingPost(post, meeting, currentUser, participants)
+	}
 
 	// Если rootID указан, создаём пост в треде
+	rootID := meeting.RootID
 	if rootID != "" {
 		// Валидация: проверяем, что rootID существует и в том же канале
 		rootPost, appErr := p.API.GetPost(rootID)
@@ -548,11 +918,27 @@ func (p *Plugin) createPost(channel *model.Channel, currentUser *model.User, par
 		}
 	}
 
-	if _, err := p.API.CreatePost(post); err != nil {
-		p.API.LogError("[Kontur] Failed to create post", "error", err.Error())
-		return err
+	// The file reflects the meeting at creation time, the "В календарь" button always serves the current version
+	if p.getConfiguration().AttachICS && meeting.OperationType != OperationInstantCall && !meeting.IsPending() {
+		if fileID, err := p.uploadMeetingICS(meeting, currentUser, participants); err != nil {
+			p.API.LogWarn("[Kontur] Failed to attach ics to post", "meeting_id", meeting.ID, "error", err.Error())
+		} else {
+			post.FileIds = []string{fileID}
+		}
+	}
+
+	createdPost, appErr := p.API.CreatePost(post)
+	if appErr != nil {
+		p.API.LogError("[Kontur] Failed to create post", "error", appErr.Error())
+		return nil, appErr
 	}
 
 	p.API.LogDebug("[Kontur] Post created successfully", "root_id", rootID)
-	return nil
+	return createdPost, nil
+}
+
+// shouldPostAsBot reports whether announcements are published by the plugin bot
+func (p *Plugin) shouldPostAsBot() bool {
+	config := p.getConfiguration()
+	return config != nil && config.PostAsBot && p.botUserID != ""
 }
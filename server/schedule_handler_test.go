@@ -0,0 +1,148 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// webhookRecorder is a webhook that answers with the given status codes in turn and records every attempt
+type webhookRecorder struct {
+	mu              sync.Mutex
+	statuses        []int
+	idempotencyKeys []string
+	requestIDs      []string
+}
+
+func (rec *webhookRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	var payload map[string]interface{}
+	json.Unmarshal(body, &payload)
+
+	rec.mu.Lock()
+	attempt := len(rec.idempotencyKeys)
+	rec.idempotencyKeys = append(rec.idempotencyKeys, r.Header.Get(HeaderIdempotencyKey))
+	requestID, _ := payload["request_id"].(string)
+	rec.requestIDs = append(rec.requestIDs, requestID)
+	status := rec.statuses[len(rec.statuses)-1]
+	if attempt < len(rec.statuses) {
+		status = rec.statuses[attempt]
+	}
+	rec.mu.Unlock()
+
+	w.WriteHeader(status)
+	if status == http.StatusOK {
+		w.Write([]byte(`{"success":true,"room_url":"https://talk.example.com/room"}`))
+		return
+	}
+	w.Write([]byte(`{"message":"unavailable"}`))
+}
+
+func (rec *webhookRecorder) attempts() int {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return len(rec.idempotencyKeys)
+}
+
+func TestSendWebhookRetriesWithSameIdempotencyKey(t *testing.T) {
+	rec := &webhookRecorder{statuses: []int{http.StatusServiceUnavailable, http.StatusOK}}
+	server := httptest.NewServer(rec)
+	defer server.Close()
+	p, _ := newTestPlugin(t)
+	p.configuration.WebhookMaxRetries = 1
+
+	data, err := p.sendWebhook(server.URL, map[string]interface{}{"request_id": "req-1"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://talk.example.com/room", data["room_url"])
+	assert.Equal(t, []string{"req-1", "req-1"}, rec.idempotencyKeys)
+	assert.Equal(t, []string{"req-1", "req-1"}, rec.requestIDs)
+}
+
+func TestSendWebhookGeneratesRequestID(t *testing.T) {
+	rec := &webhookRecorder{statuses: []int{http.StatusBadGateway, http.StatusOK}}
+	server := httptest.NewServer(rec)
+	defer server.Close()
+	p, _ := newTestPlugin(t)
+
+	_, err := p.sendWebhookWithin(server.URL, map[string]interface{}{}, 10*time.Second, 1)
+
+	require.NoError(t, err)
+	require.Len(t, rec.idempotencyKeys, 2)
+	assert.NotEmpty(t, rec.idempotencyKeys[0])
+	assert.Equal(t, rec.idempotencyKeys[0], rec.idempotencyKeys[1])
+	assert.Equal(t, rec.idempotencyKeys, rec.requestIDs)
+}
+
+func TestSendWebhookDoesNotRetryClientErrors(t *testing.T) {
+	rec := &webhookRecorder{statuses: []int{http.StatusBadRequest}}
+	server := httptest.NewServer(rec)
+	defer server.Close()
+	p, _ := newTestPlugin(t)
+
+	_, err := p.sendWebhookWithin(server.URL, map[string]interface{}{}, 10*time.Second, WebhookMaxRetriesLimit)
+
+	require.Error(t, err)
+	assert.Equal(t, 1, rec.attempts())
+}
+
+func TestSendWebhookGivesUpAfterMaxRetries(t *testing.T) {
+	rec := &webhookRecorder{statuses: []int{http.StatusInternalServerError}}
+	server := httptest.NewServer(rec)
+	defer server.Close()
+	p, _ := newTestPlugin(t)
+
+	_, err := p.sendWebhookWithin(server.URL, map[string]interface{}{}, 10*time.Second, 1)
+
+	require.Error(t, err)
+	assert.Equal(t, 2, rec.attempts())
+}
+
+func TestSendWebhookReturnsBuildErrorsImmediately(t *testing.T) {
+	p, _ := newTestPlugin(t)
+	started := time.Now()
+
+	_, err := p.sendWebhookWithin("http://[::1", map[string]interface{}{}, 10*time.Second, WebhookMaxRetriesLimit)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to build request")
+	assert.Less(t, time.Since(started), WebhookRetryBaseDelay/2)
+}
+
+func TestSendWebhookStopsAtOverallDeadline(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+	p, _ := newTestPlugin(t)
+	started := time.Now()
+
+	_, err := p.sendWebhookWithin(server.URL, map[string]interface{}{}, 300*time.Millisecond, WebhookMaxRetriesLimit)
+
+	require.Error(t, err)
+	assert.Less(t, time.Since(started), 2*time.Second)
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	for attempt := 0; attempt < 10; attempt++ {
+		want := WebhookRetryBaseDelay << uint(attempt)
+		if want > WebhookRetryMaxDelay {
+			want = WebhookRetryMaxDelay
+		}
+		delay := webhookRetryDelay(attempt)
+		assert.GreaterOrEqual(t, delay, want/2, "attempt %d", attempt)
+		assert.LessOrEqual(t, delay, want, "attempt %d", attempt)
+	}
+}
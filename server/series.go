@@ -0,0 +1,378 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+)
+
+// Series statuses
+const (
+	SeriesStatusActive    = "active"
+	SeriesStatusFinished  = "finished"
+	SeriesStatusCancelled = "cancelled"
+)
+
+// Recurring meeting settings
+const (
+	SeriesLookahead      = 24 * time.Hour // Occurrences are announced this long before they start
+	seriesLockKey        = "job_lock_series"
+	seriesLockTTLSeconds = 50
+)
+
+// Series is a recurring meeting: the template of its occurrences and the shared room
+type Series struct {
+	ID                        string   `json:"id"`
+	Status                    string   `json:"status"`
+	RRule                     string   `json:"rrule"`
+	Title                     string   `json:"title"`
+	ChannelID                 string   `json:"channel_id"`
+	TeamID                    string   `json:"team_id"`
+	RootID                    string   `json:"root_id"`
+	OrganizerID               string   `json:"organizer_id"`
+	ParticipantIDs            []string `json:"participant_ids"`
+	StartAt                   int64    `json:"start_at"` // First occurrence, Unix time in milliseconds
+	DurationMinutes           int      `json:"duration_minutes"`
+	Timezone                  string   `json:"timezone"`
+	Locale                    string   `json:"locale,omitempty"` // Organizer's locale for occurrence posts
+	NotifyParticipants        bool     `json:"notify_participants"`
+	CreateGoogleCalendarEvent bool     `json:"create_google_calendar_event"`
+	RoomURL                   string   `json:"room_url"` // Reused by every occurrence
+	RoomID                    string   `json:"room_id"`
+	Generated                 int      `json:"generated"`                 // Occurrences produced so far, counted against COUNT
+	LastStartAt               int64    `json:"last_start_at"`             // Start of the latest produced occurrence
+	NextStartAt               int64    `json:"next_start_at,omitempty"`   // Occurrence reserved before its room is created
+	NextMeetingID             string   `json:"next_meeting_id,omitempty"` // Meeting ID of that occurrence, reused by retries
+	CreateAt                  int64    `json:"create_at"`
+	UpdateAt                  int64    `json:"update_at"`
+}
+
+// newSeries builds a series for a schedule request with recurrence; the first occurrence starts at scheduledAt
+func newSeries(req *ScheduleRequest, rule *RecurrenceRule, organizer *model.User, channel *model.Channel, participants []*model.User, scheduledAt time.Time) *Series {
+	title := ""
+	if req.Title != nil {
+		title = *req.Title
+	}
+
+	timezone := req.Timezone
+	if timezone == "" {
+		timezone = DefaultTimezone
+	}
+
+	participantIDs := make([]string, 0, len(participants))
+	for _, user := range participants {
+		participantIDs = append(participantIDs, user.Id)
+	}
+
+	now := model.GetMillis()
+	return &Series{
+		ID:                        model.NewId(),
+		Status:                    SeriesStatusActive,
+		RRule:                     rule.String(),
+		Title:                     title,
+		ChannelID:                 channel.Id,
+		TeamID:                    channel.TeamId,
+		RootID:                    req.RootID,
+		OrganizerID:               organizer.Id,
+		ParticipantIDs:            participantIDs,
+		StartAt:                   model.GetMillisForTime(scheduledAt),
+		DurationMinutes:           req.DurationMinutes,
+		Timezone:                  timezone,
+		Locale:                    req.locale,
+		NotifyParticipants:        req.NotifyParticipants,
+		CreateGoogleCalendarEvent: req.CreateGoogleCalendarEvent,
+		CreateAt:                  now,
+		UpdateAt:                  now,
+	}
+}
+
+// StartTime returns the first occurrence start in the series timezone
+func (s *Series) StartTime() time.Time {
+	return model.GetTimeForMillis(s.StartAt).In(loadLocation(s.Timezone))
+}
+
+// rule parses the stored RRULE
+func (s *Series) rule() (*RecurrenceRule, error) {
+	rule, err := parseRRule(s.RRule, loadLocation(s.Timezone), s.Locale)
+	if err != nil {
+		return nil, err
+	}
+	return rule, rule.validate(s.StartTime(), s.Locale)
+}
+
+// prepareSeries validates the recurrence of a schedule request and attaches a new series to it
+func (p *Plugin) prepareSeries(req *ScheduleRequest, organizer *model.User, channel *model.Channel, participants []*model.User, scheduledAt time.Time) *RequestError {
+	rule, err := parseRecurrence(req.Recurrence, scheduledAt.In(loadLocation(req.Timezone)), req.locale)
+	if err != nil {
+		return &RequestError{StatusCode: http.StatusBadRequest, Field: RequestFieldRecurrence, Message: err.Error()}
+	}
+	req.series = newSeries(req, rule, organizer, channel, participants, scheduledAt)
+	return nil
+}
+
+// startSeries stores the series after its first occurrence was created
+func (p *Plugin) startSeries(series *Series, first *Meeting) {
+	series.RoomURL = first.RoomURL
+	series.RoomID = first.RoomID
+	series.Generated = 1
+	series.LastStartAt = first.StartAt
+
+	if err := p.saveSeries(series); err != nil {
+		p.API.LogError("[Kontur] Failed to save series", "series_id", series.ID, "error", err.Error())
+		return
+	}
+	p.API.LogInfo("[Kontur] Series created", "series_id", series.ID, "rrule", series.RRule)
+}
+
+// generateSeriesOccurrences creates the next occurrence of every active series once it is within SeriesLookahead
+func (p *Plugin) generateSeriesOccurrences(now time.Time) {
+	seriesList, err := p.getActiveSeries()
+	if err != nil {
+		p.API.LogError("[Kontur] Failed to load series", "error", err.Error())
+		return
+	}
+
+	done := []string{}
+	for _, series := range seriesList {
+		if series.Status != SeriesStatusActive {
+			done = append(done, series.ID)
+			continue
+		}
+		if !p.advanceSeries(series, now) {
+			done = append(done, series.ID)
+		}
+	}
+
+	if len(done) > 0 {
+		if err := p.removeFromIndex(kvActiveSeriesIndexKey, done...); err != nil {
+			p.API.LogError("[Kontur] Failed to prune series", "error", err.Error())
+		}
+	}
+}
+
+// advanceSeries creates the next occurrence if it is due and reports whether the series goes on
+func (p *Plugin) advanceSeries(series *Series, now time.Time) bool {
+	rule, err := series.rule()
+	if err != nil {
+		p.API.LogError("[Kontur] Invalid series rule", "series_id", series.ID, "rrule", series.RRule, "error", err.Error())
+		return false
+	}
+
+	dtstart := series.StartTime()
+	generated := series.Generated
+	next, ok := rule.nextAfter(dtstart, model.GetTimeForMillis(series.LastStartAt), generated)
+	// Occurrences missed while no node ran the job are skipped but still count against COUNT
+	for ok && next.Before(now) {
+		generated++
+		next, ok = rule.nextAfter(dtstart, next, generated)
+	}
+
+	if !ok {
+		if _, err := p.modifySeries(series.ID, func(s *Series) bool {
+			if s.Status != SeriesStatusActive {
+				return false
+			}
+			s.Status = SeriesStatusFinished
+			return true
+		}); err != nil {
+			p.API.LogError("[Kontur] Failed to finish series", "series_id", series.ID, "error", err.Error())
+		}
+		p.API.LogInfo("[Kontur] Series finished", "series_id", series.ID)
+		return false
+	}
+	if next.Sub(now) > SeriesLookahead {
+		return true
+	}
+
+	// Reserve the occurrence on the fresh series before calling the provider, so a cancellation
+	// or a concurrent run is noticed and a retry reuses the same meeting ID
+	nextAt := model.GetMillisForTime(next)
+	due := false
+	reserved, err := p.modifySeries(series.ID, func(s *Series) bool {
+		due = s.Status == SeriesStatusActive && s.LastStartAt < nextAt
+		if !due || s.NextStartAt == nextAt {
+			return false
+		}
+		s.NextStartAt = nextAt
+		s.NextMeetingID = model.NewId()
+		return true
+	})
+	if err != nil {
+		p.API.LogError("[Kontur] Failed to reserve series occurrence", "series_id", series.ID, "error", err.Error())
+		return true
+	}
+	if !due {
+		return reserved.Status == SeriesStatusActive
+	}
+
+	meeting, reqErr := p.createSeriesOccurrence(reserved, next)
+	if reqErr != nil && reqErr.StatusCode == http.StatusForbidden {
+		p.stopSeries(reserved, reqErr.Message)
+		return false
+	}
+	if reqErr != nil {
+		// Retried on the next tick with the same meeting ID and request ID, so neither a second room nor a second post appears
+		p.API.LogError("[Kontur] Failed to create series occurrence", "series_id", series.ID, "start_at", next.Format(time.RFC3339), "error", reqErr.Message)
+		return true
+	}
+
+	cancelled := false
+	if _, err := p.modifySeries(series.ID, func(s *Series) bool {
+		if s.Status == SeriesStatusCancelled {
+			cancelled = true
+			return false
+		}
+		s.Generated = generated + 1
+		s.LastStartAt = meeting.StartAt
+		s.NextStartAt, s.NextMeetingID = 0, ""
+		if s.RoomURL == "" && !meeting.IsPending() {
+			s.RoomURL = meeting.RoomURL
+			s.RoomID = meeting.RoomID
+		}
+		return true
+	}); err != nil {
+		p.API.LogError("[Kontur] Failed to save series progress", "series_id", series.ID, "error", err.Error())
+	}
+	if cancelled {
+		// The series was cancelled while the occurrence was being created
+		if reqErr := p.cancelMeeting(meeting, series.OrganizerID); reqErr != nil {
+			p.API.LogError("[Kontur] Failed to cancel occurrence of cancelled series", "meeting_id", meeting.ID, "error", reqErr.Message)
+		}
+		return false
+	}
+
+	p.API.LogInfo("[Kontur] Series occurrence created", "series_id", series.ID, "meeting_id", meeting.ID)
+	return true
+}
+
+// createSeriesOccurrence creates the reserved meeting of the series starting at the given time, or returns it
+// if a previous attempt already stored it. It bypasses parseDateTime, so its 30-day limit applies only to the first occurrence.
+func (p *Plugin) createSeriesOccurrence(series *Series, start time.Time) (*Meeting, *RequestError) {
+	meeting, err := p.getMeeting(series.NextMeetingID)
+	if err == nil {
+		return meeting, nil
+	}
+	if err != ErrMeetingNotFound {
+		return nil, &RequestError{StatusCode: http.StatusInternalServerError, Field: RequestFieldGeneral, Message: err.Error()}
+	}
+
+	organizer, err := p.getUserSafely(series.OrganizerID)
+	if err != nil {
+		return nil, &RequestError{StatusCode: http.StatusNotFound, Field: RequestFieldUserID, Message: err.Error()}
+	}
+	channel, err := p.getChannelSafely(series.ChannelID)
+	if err != nil {
+		return nil, &RequestError{StatusCode: http.StatusNotFound, Field: RequestFieldChannelID, Message: err.Error()}
+	}
+	// The organizer may have left the channel or lost the right to post since the series was created
+	if err := p.checkChannelAccess(series.OrganizerID, channel, series.Locale); err != nil {
+		return nil, &RequestError{StatusCode: http.StatusForbidden, Field: RequestFieldChannelID, Message: err.Error()}
+	}
+
+	participants := make([]*model.User, 0, len(series.ParticipantIDs))
+	for _, userID := range series.ParticipantIDs {
+		user, err := p.getUserSafely(userID)
+		if err != nil {
+			p.API.LogWarn("[Kontur] Failed to get series participant", "series_id", series.ID, "user_id", userID)
+			continue
+		}
+		participants = append(participants, user)
+	}
+
+	title := series.Title
+	req := &ScheduleRequest{
+		ChannelID:                 series.ChannelID,
+		TeamID:                    series.TeamID,
+		UserID:                    series.OrganizerID,
+		StartAt:                   start.UTC().Format(time.RFC3339),
+		Timezone:                  series.Timezone,
+		DurationMinutes:           series.DurationMinutes,
+		Title:                     &title,
+		ParticipantIDs:            series.ParticipantIDs,
+		NotifyParticipants:        series.NotifyParticipants,
+		CreateGoogleCalendarEvent: series.CreateGoogleCalendarEvent,
+		RootID:                    series.RootID,
+		RequestID:                 fmt.Sprintf("%s-%d", series.ID, start.Unix()),
+		locale:                    series.Locale,
+		series:                    series,
+		meetingID:                 series.NextMeetingID,
+	}
+
+	return p.createMeeting(req, organizer, channel, participants, start)
+}
+
+// stopSeries cancels a series that can no longer post its occurrences and tells the organizer why
+func (p *Plugin) stopSeries(series *Series, reason string) {
+	if _, err := p.modifySeries(series.ID, func(s *Series) bool {
+		if s.Status != SeriesStatusActive {
+			return false
+		}
+		s.Status = SeriesStatusCancelled
+		s.NextStartAt, s.NextMeetingID = 0, ""
+		return true
+	}); err != nil {
+		p.API.LogError("[Kontur] Failed to stop series", "series_id", series.ID, "error", err.Error())
+	}
+	p.API.LogWarn("[Kontur] Series stopped", "series_id", series.ID, "reason", reason)
+
+	title := series.Title
+	if title == "" {
+		title = tr(series.Locale, "meeting.default_title")
+	}
+	if err := p.sendDirectMessage(series.OrganizerID, tr(series.Locale, "meeting.series_stopped", title, reason)); err != nil {
+		p.API.LogWarn("[Kontur] Failed to notify organizer about stopped series", "series_id", series.ID, "error", err.Error())
+	}
+}
+
+// setSeriesRoom remembers the room delivered later for a series whose first occurrence was pending
+func (p *Plugin) setSeriesRoom(seriesID, roomURL, roomID string) {
+	if _, err := p.modifySeries(seriesID, func(s *Series) bool {
+		if s.RoomURL != "" {
+			return false
+		}
+		s.RoomURL = roomURL
+		s.RoomID = roomID
+		return true
+	}); err != nil {
+		p.API.LogError("[Kontur] Failed to save series room", "series_id", seriesID, "error", err.Error())
+	}
+}
+
+// cancelSeries stops generating occurrences and cancels the already announced upcoming ones
+func (p *Plugin) cancelSeries(meeting *Meeting, userID string) *RequestError {
+	locale := p.userLocale(userID)
+	if _, err := p.modifySeries(meeting.SeriesID, func(s *Series) bool {
+		if s.Status == SeriesStatusCancelled {
+			return false
+		}
+		s.Status = SeriesStatusCancelled
+		return true
+	}); err != nil {
+		p.API.LogError("[Kontur] Failed to cancel series", "series_id", meeting.SeriesID, "error", err.Error())
+		return &RequestError{StatusCode: http.StatusInternalServerError, Field: RequestFieldGeneral,
+			Message: tr(locale, "meeting.series_cancel_failed")}
+	}
+	if err := p.removeFromIndex(kvActiveSeriesIndexKey, meeting.SeriesID); err != nil {
+		p.API.LogError("[Kontur] Failed to unindex series", "series_id", meeting.SeriesID, "error", err.Error())
+	}
+
+	meetings, err := p.getChannelMeetings(meeting.ChannelID)
+	if err != nil {
+		p.API.LogError("[Kontur] Failed to load series occurrences", "series_id", meeting.SeriesID, "error", err.Error())
+		return nil
+	}
+	now := time.Now()
+	for _, occurrence := range meetings {
+		if occurrence.SeriesID != meeting.SeriesID || occurrence.IsCancelled() || occurrence.EndTime().Before(now) {
+			continue
+		}
+		if reqErr := p.cancelMeeting(occurrence, userID); reqErr != nil {
+			return reqErr
+		}
+	}
+
+	p.API.LogInfo("[Kontur] Series cancelled", "series_id", meeting.SeriesID, RequestFieldUserID, userID)
+	return nil
+}
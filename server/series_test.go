@@ -0,0 +1,134 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/mattermost/mattermost-server/v6/plugin/plugintest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newSeriesTestPlugin stores a daily series whose next occurrence starts in two hours
+func newSeriesTestPlugin(t *testing.T, now time.Time) (*Plugin, *plugintest.API, *Series, time.Time) {
+	p, api := newTestPlugin(t)
+	p.configuration.MeetingProvider = ProviderJitsi
+
+	// Occurrences start on whole minutes
+	first := now.Add(2*time.Hour - 24*time.Hour).Truncate(time.Minute)
+	series := &Series{
+		ID:              "series1",
+		Status:          SeriesStatusActive,
+		RRule:           "FREQ=DAILY",
+		ChannelID:       "channel",
+		OrganizerID:     "organizer",
+		ParticipantIDs:  []string{"participant"},
+		StartAt:         model.GetMillisForTime(first),
+		DurationMinutes: 30,
+		Timezone:        DefaultTimezone,
+		Generated:       1,
+		LastStartAt:     model.GetMillisForTime(first),
+	}
+	require.NoError(t, p.saveSeries(series))
+	return p, api, series, first.Add(24 * time.Hour)
+}
+
+func TestAdvanceSeriesStopsWhenCancelledMeanwhile(t *testing.T) {
+	now := time.Now()
+	p, _, series, _ := newSeriesTestPlugin(t, now)
+
+	cancelled := *series
+	cancelled.Status = SeriesStatusCancelled
+	require.NoError(t, p.saveSeries(&cancelled))
+
+	// The stale copy is still active: the occurrence must not be created anyway
+	assert.False(t, p.advanceSeries(series, now))
+
+	stored, err := p.getSeries(series.ID)
+	require.NoError(t, err)
+	assert.Equal(t, SeriesStatusCancelled, stored.Status)
+	assert.Empty(t, stored.NextMeetingID)
+	assert.Equal(t, 1, stored.Generated)
+}
+
+func TestAdvanceSeriesRetryReusesReservation(t *testing.T) {
+	now := time.Now()
+	p, api, series, _ := newSeriesTestPlugin(t, now)
+	// Without a room template the provider fails after the occurrence was reserved
+	api.On("GetUser", "organizer").Return(&model.User{Id: "organizer"}, nil)
+	api.On("GetUser", "participant").Return(&model.User{Id: "participant"}, nil)
+	api.On("GetChannel", "channel").Return(&model.Channel{Id: "channel", Type: model.ChannelTypeOpen}, nil)
+	api.On("GetChannelMember", "channel", "organizer").Return(&model.ChannelMember{ChannelId: "channel", UserId: "organizer"}, nil)
+	api.On("HasPermissionToChannel", "organizer", "channel", model.PermissionCreatePost).Return(true)
+
+	assert.True(t, p.advanceSeries(series, now))
+	first, err := p.getSeries(series.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, first.NextMeetingID)
+
+	assert.True(t, p.advanceSeries(first, now))
+	second, err := p.getSeries(series.ID)
+	require.NoError(t, err)
+	assert.Equal(t, first.NextMeetingID, second.NextMeetingID)
+	assert.Equal(t, first.NextStartAt, second.NextStartAt)
+	assert.Equal(t, 1, second.Generated)
+}
+
+func TestAdvanceSeriesReusesStoredOccurrence(t *testing.T) {
+	now := time.Now()
+	p, _, series, next := newSeriesTestPlugin(t, now)
+
+	// A previous run stored the occurrence but failed to record the series progress
+	series.NextStartAt = model.GetMillisForTime(next)
+	series.NextMeetingID = "occurrence1"
+	require.NoError(t, p.saveSeries(series))
+	require.NoError(t, p.saveMeeting(&Meeting{
+		ID:              "occurrence1",
+		OperationType:   OperationScheduledMeeting,
+		Status:          MeetingStatusScheduled,
+		ChannelID:       "channel",
+		OrganizerID:     "organizer",
+		StartAt:         model.GetMillisForTime(next),
+		DurationMinutes: 30,
+		SeriesID:        series.ID,
+		RoomURL:         "https://meet.example.com/room",
+	}))
+
+	// No provider call and no post: the mock would fail on any unexpected API call
+	assert.True(t, p.advanceSeries(series, now))
+
+	stored, err := p.getSeries(series.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, stored.Generated)
+	assert.Equal(t, model.GetMillisForTime(next), stored.LastStartAt)
+	assert.Empty(t, stored.NextMeetingID)
+	assert.Equal(t, "https://meet.example.com/room", stored.RoomURL)
+}
+
+func TestAdvanceSeriesStopsWhenOrganizerLostChannelAccess(t *testing.T) {
+	now := time.Now()
+	p, api, series, _ := newSeriesTestPlugin(t, now)
+	p.botUserID = "bot"
+	series.Title = "Standup"
+	series.Locale = LocaleEn
+	require.NoError(t, p.saveSeries(series))
+	api.On("GetUser", "organizer").Return(&model.User{Id: "organizer"}, nil)
+	api.On("GetChannel", "channel").Return(&model.Channel{Id: "channel", Type: model.ChannelTypeOpen}, nil)
+	api.On("GetChannelMember", "channel", "organizer").Return(nil, model.NewAppError("GetChannelMember", "not_found", nil, "", 404))
+	api.On("GetDirectChannel", "bot", "organizer").Return(&model.Channel{Id: "dm"}, nil)
+	var notice string
+	api.On("CreatePost", mock.AnythingOfType("*model.Post")).Run(func(args mock.Arguments) {
+		notice = args.Get(0).(*model.Post).Message
+	}).Return(&model.Post{}, nil)
+
+	assert.False(t, p.advanceSeries(series, now))
+
+	stored, err := p.getSeries(series.ID)
+	require.NoError(t, err)
+	assert.Equal(t, SeriesStatusCancelled, stored.Status)
+	assert.Empty(t, stored.NextMeetingID)
+	assert.Equal(t, 1, stored.Generated)
+	assert.Equal(t, tr(LocaleEn, "meeting.series_stopped", "Standup", tr(LocaleEn, "access.not_channel_member")), notice)
+}
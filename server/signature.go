@@ -0,0 +1,51 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// signatureScheme prefixes the hex digest in the X-Kontur-Signature header
+const signatureScheme = "sha256="
+
+// SignatureTolerance is the maximum clock skew of a signed inbound request; older requests are replays
+const SignatureTolerance = 5 * time.Minute
+
+// signPayload returns the X-Kontur-Signature value: HMAC-SHA256 of "<timestamp>.<body>"
+func signPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signatureScheme + hex.EncodeToString(mac.Sum(nil))
+}
+
+// signatureTimestamp returns the X-Kontur-Timestamp value for the given time (Unix seconds)
+func signatureTimestamp(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 10)
+}
+
+// verifySignature checks the X-Kontur-Signature and X-Kontur-Timestamp of an inbound request
+func verifySignature(secret, timestamp, signature string, body []byte, now time.Time) error {
+	if timestamp == "" || signature == "" {
+		return fmt.Errorf("missing signature headers")
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %q", timestamp)
+	}
+	skew := now.Sub(time.Unix(seconds, 0))
+	if skew > SignatureTolerance || skew < -SignatureTolerance {
+		return fmt.Errorf("timestamp is outside the allowed window")
+	}
+
+	if !hmac.Equal([]byte(signPayload(secret, timestamp, body)), []byte(signature)) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
@@ -0,0 +1,316 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+)
+
+// Slot suggestion defaults and limits
+const (
+	SuggestSlotsPath           = "suggest-slots" // /api/meetings/suggest-slots
+	slotDefaultWorkdayStart    = "09:00"
+	slotDefaultWorkdayEnd      = "18:00"
+	slotDefaultStepMinutes     = 30
+	slotMinStepMinutes         = 5
+	slotDefaultWindow          = 7 * 24 * time.Hour
+	slotDefaultLimit           = 5
+	slotMaxLimit               = 20
+	slotMaxParticipants        = 50
+	slotBufferMinutes          = 15 // A slot right next to another meeting ranks lower
+	slotCoreHoursMarginMinutes = 60 // A slot in the first or last hour of someone's workday ranks lower
+)
+
+// SlotSuggestionRequest asks for free time of the acting user and participants
+type SlotSuggestionRequest struct {
+	ChannelID       string   `json:"channel_id"` // Optional: restricts participants like scheduling does
+	TeamID          string   `json:"team_id"`
+	ParticipantIDs  []string `json:"participant_ids"`
+	DurationMinutes int      `json:"duration_minutes"`
+	From            string   `json:"from"`          // RFC3339 or YYYY-MM-DD, now by default
+	To              string   `json:"to"`            // RFC3339 or YYYY-MM-DD (inclusive), 7 days after from by default
+	Timezone        string   `json:"timezone"`      // Zone of dates without offset and of the response, the profile zone by default
+	WorkdayStart    string   `json:"workday_start"` // HH:MM in each user's own timezone, 09:00 by default
+	WorkdayEnd      string   `json:"workday_end"`   // HH:MM, 18:00 by default
+	IncludeWeekends bool     `json:"include_weekends"`
+	StepMinutes     int      `json:"step_minutes"` // Granularity of slot starts from local midnight, 30 by default
+	Limit           int      `json:"limit"`        // Number of slots returned, 5 by default
+}
+
+// SuggestedSlot is a time when everyone is free
+type SuggestedSlot struct {
+	StartTimeUTC   string `json:"start_time_utc"`
+	EndTimeUTC     string `json:"end_time_utc"`
+	StartTimeLocal string `json:"start_time_local"` // In the request timezone
+	Score          int    `json:"score"`
+}
+
+// handleSuggestSlots handles POST /api/meetings/suggest-slots
+func (p *Plugin) handleSuggestSlots(w http.ResponseWriter, r *http.Request, userID, locale string) {
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, RequestFieldGeneral, tr(locale, "request.method_not_allowed"))
+		return
+	}
+
+	var req SlotSuggestionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, RequestFieldGeneral, tr(locale, "request.invalid_json", err.Error()))
+		return
+	}
+
+	slots, loc, reqErr := p.suggestSlots(&req, userID, locale, time.Now())
+	if reqErr != nil {
+		writeRequestError(w, reqErr)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	response := map[string]interface{}{
+		"slots":    slots,
+		"timezone": loc.String(),
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		p.API.LogError("[Kontur] Failed to encode slots response", "error", err.Error())
+	}
+}
+
+// suggestSlots finds ranked non-overlapping slots when the acting user and all participants are free
+// and inside their working hours. Slots respect the scheduling rules: 5-480 minutes, not in the past,
+// not later than 30 days ahead.
+func (p *Plugin) suggestSlots(req *SlotSuggestionRequest, userID, locale string, now time.Time) ([]*SuggestedSlot, *time.Location, *RequestError) {
+	if req.DurationMinutes < 5 || req.DurationMinutes > 480 {
+		return nil, nil, &RequestError{StatusCode: http.StatusBadRequest, Field: "duration_minutes",
+			Message: tr(locale, "slots.duration_range", 5, 480)}
+	}
+	if len(req.ParticipantIDs) > slotMaxParticipants {
+		return nil, nil, &RequestError{StatusCode: http.StatusBadRequest, Field: RequestFieldParticipantIDs,
+			Message: tr(locale, "slots.too_many_participants", slotMaxParticipants)}
+	}
+	// Any divisor of a day keeps the slots of every day on the same grid
+	step := req.StepMinutes
+	if step == 0 {
+		step = slotDefaultStepMinutes
+	}
+	if step < slotMinStepMinutes || (24*60)%step != 0 {
+		return nil, nil, &RequestError{StatusCode: http.StatusBadRequest, Field: "step_minutes",
+			Message: tr(locale, "slots.invalid_step", slotMinStepMinutes)}
+	}
+
+	organizer, err := p.getUserSafely(userID)
+	if err != nil {
+		return nil, nil, &RequestError{StatusCode: http.StatusNotFound, Field: RequestFieldUserID, Message: err.Error()}
+	}
+	participants := make([]*model.User, 0, len(req.ParticipantIDs))
+	for _, id := range req.ParticipantIDs {
+		user, err := p.getUserSafely(id)
+		if err != nil {
+			return nil, nil, &RequestError{StatusCode: http.StatusBadRequest, Field: RequestFieldParticipantIDs,
+				Message: tr(locale, "schedule.user_not_found", id)}
+		}
+		participants = append(participants, user)
+	}
+
+	if req.ChannelID != "" {
+		channel, err := p.getChannelSafely(req.ChannelID)
+		if err != nil {
+			return nil, nil, &RequestError{StatusCode: http.StatusNotFound, Field: RequestFieldChannelID, Message: err.Error()}
+		}
+		if err := p.checkChannelAccess(userID, channel, locale); err != nil {
+			return nil, nil, &RequestError{StatusCode: http.StatusForbidden, Field: RequestFieldChannelID, Message: err.Error()}
+		}
+		if err := p.checkParticipantsAccess(participants, channel, req.TeamID, locale); err != nil {
+			return nil, nil, &RequestError{StatusCode: http.StatusForbidden, Field: RequestFieldParticipantIDs, Message: err.Error()}
+		}
+	}
+
+	loc := userLocation(organizer)
+	if req.Timezone != "" {
+		loc = loadLocation(req.Timezone)
+	}
+
+	workdayStart, err := parseClock(req.WorkdayStart, slotDefaultWorkdayStart)
+	if err != nil {
+		return nil, nil, &RequestError{StatusCode: http.StatusBadRequest, Field: "workday_start",
+			Message: tr(locale, "slots.invalid_workday")}
+	}
+	// A workday too short for the meeting is blamed on its end, the start being valid by now
+	workdayEnd, err := parseClock(req.WorkdayEnd, slotDefaultWorkdayEnd)
+	if err != nil || workdayEnd-workdayStart < req.DurationMinutes {
+		return nil, nil, &RequestError{StatusCode: http.StatusBadRequest, Field: "workday_end",
+			Message: tr(locale, "slots.invalid_workday")}
+	}
+
+	from, to, reqErr := slotWindow(req, loc, locale, now)
+	if reqErr != nil {
+		return nil, nil, reqErr
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = slotDefaultLimit
+	} else if limit > slotMaxLimit {
+		limit = slotMaxLimit
+	}
+
+	duration := time.Duration(req.DurationMinutes) * time.Minute
+	users := meetingUsers(organizer, participants)
+	buffer := time.Duration(slotBufferMinutes) * time.Minute
+	busy := p.getBusyIntervals(users, from.Add(-buffer), to.Add(duration+buffer), "")
+
+	start := alignSlotStart(from.In(loc), step)
+
+	candidates := []*SuggestedSlot{}
+	for ; !start.After(to); start = start.Add(time.Duration(step) * time.Minute) {
+		end := start.Add(duration)
+		score, ok := scoreSlot(users, busy, start, end, workdayStart, workdayEnd, req.IncludeWeekends)
+		if !ok {
+			continue
+		}
+		candidates = append(candidates, &SuggestedSlot{
+			StartTimeUTC:   start.UTC().Format(time.RFC3339),
+			EndTimeUTC:     end.UTC().Format(time.RFC3339),
+			StartTimeLocal: start.Format(time.RFC3339),
+			Score:          score,
+		})
+	}
+
+	// Best slots first, earlier ones among equals; overlapping suggestions are dropped
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	slots := make([]*SuggestedSlot, 0, limit)
+	for _, candidate := range candidates {
+		if len(slots) == limit {
+			break
+		}
+		if !overlapsSlots(candidate, slots) {
+			slots = append(slots, candidate)
+		}
+	}
+
+	p.API.LogDebug("[Kontur] Slots suggested", "user_count", len(users), "candidates", len(candidates), "returned", len(slots))
+	return slots, loc, nil
+}
+
+// slotWindow resolves the search window, clamped to [now, now + 30 days]
+func slotWindow(req *SlotSuggestionRequest, loc *time.Location, locale string, now time.Time) (time.Time, time.Time, *RequestError) {
+	from := now
+	if req.From != "" {
+		parsed, err := parseWindowBound(req.From, loc, locale, false)
+		if err != nil {
+			return time.Time{}, time.Time{}, &RequestError{StatusCode: http.StatusBadRequest, Field: "from", Message: err.Error()}
+		}
+		if parsed.After(now) {
+			from = parsed
+		}
+	}
+
+	to := from.Add(slotDefaultWindow)
+	if req.To != "" {
+		parsed, err := parseWindowBound(req.To, loc, locale, true)
+		if err != nil {
+			return time.Time{}, time.Time{}, &RequestError{StatusCode: http.StatusBadRequest, Field: "to", Message: err.Error()}
+		}
+		to = parsed
+	}
+	if maxDate := now.Add(30 * 24 * time.Hour); to.After(maxDate) {
+		to = maxDate
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, &RequestError{StatusCode: http.StatusBadRequest, Field: "to",
+			Message: tr(locale, "slots.invalid_window", 30)}
+	}
+	return from, to, nil
+}
+
+// parseWindowBound parses RFC3339 or a date; the end of the window includes the whole day
+func parseWindowBound(value string, loc *time.Location, locale string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	day, err := time.ParseInLocation("2006-01-02", value, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s", tr(locale, "slots.invalid_date", value))
+	}
+	if endOfDay {
+		return day.AddDate(0, 0, 1).Add(-time.Minute), nil
+	}
+	return day, nil
+}
+
+// alignSlotStart rounds the time up to the next multiple of the step counted from its local midnight
+func alignSlotStart(t time.Time, step int) time.Time {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	minutes := int(math.Ceil(t.Sub(midnight).Minutes()))
+	if offset := minutes % step; offset != 0 {
+		minutes += step - offset
+	}
+	return midnight.Add(time.Duration(minutes) * time.Minute)
+}
+
+// parseClock parses HH:MM into minutes since midnight
+func parseClock(value, fallback string) (int, error) {
+	if value == "" {
+		value = fallback
+	}
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// scoreSlot checks that every user is free and within working hours in their own timezone.
+// Each user adds a point when the slot isn't squeezed next to another meeting and one more
+// when it is away from the edges of their workday.
+func scoreSlot(users []*model.User, busy map[string][]BusyInterval, start, end time.Time, workdayStart, workdayEnd int, includeWeekends bool) (int, bool) {
+	buffer := time.Duration(slotBufferMinutes) * time.Minute
+	score := 0
+
+	for _, user := range users {
+		loc := userLocation(user)
+		localStart, localEnd := start.In(loc), end.In(loc)
+		if !includeWeekends && (localStart.Weekday() == time.Saturday || localStart.Weekday() == time.Sunday) {
+			return 0, false
+		}
+		startMinute := localStart.Hour()*60 + localStart.Minute()
+		endMinute := startMinute + int(end.Sub(start).Minutes())
+		if localEnd.YearDay() != localStart.YearDay() || startMinute < workdayStart || endMinute > workdayEnd {
+			return 0, false
+		}
+
+		adjacent := false
+		for _, interval := range busy[user.Id] {
+			if interval.Start.Before(end) && start.Before(interval.End) {
+				return 0, false
+			}
+			if interval.Start.Before(end.Add(buffer)) && start.Add(-buffer).Before(interval.End) {
+				adjacent = true
+			}
+		}
+		if !adjacent {
+			score++
+		}
+		if startMinute >= workdayStart+slotCoreHoursMarginMinutes && endMinute <= workdayEnd-slotCoreHoursMarginMinutes {
+			score++
+		}
+	}
+	return score, true
+}
+
+// overlapsSlots reports whether the slot overlaps any of the chosen ones
+func overlapsSlots(slot *SuggestedSlot, chosen []*SuggestedSlot) bool {
+	for _, other := range chosen {
+		if slot.StartTimeUTC < other.EndTimeUTC && other.StartTimeUTC < slot.EndTimeUTC {
+			return true
+		}
+	}
+	return false
+}
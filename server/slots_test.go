@@ -0,0 +1,210 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggestSlotsValidatesStep(t *testing.T) {
+	for _, tc := range []struct {
+		step int
+		ok   bool
+	}{
+		{0, true},
+		{15, true},
+		{45, true},
+		{90, true},
+		{-30, false},
+		{1, false},
+		{7, false},
+		{25, false},
+	} {
+		p, api := newTestPlugin(t)
+		api.On("GetUser", "organizer").Return(&model.User{Id: "organizer"}, nil).Maybe()
+		now := time.Date(2025, 1, 20, 10, 7, 0, 0, loadLocation(DefaultTimezone))
+
+		_, _, reqErr := p.suggestSlots(&SlotSuggestionRequest{DurationMinutes: 30, StepMinutes: tc.step}, "organizer", LocaleEn, now)
+
+		if tc.ok {
+			assert.Nil(t, reqErr, "step %d", tc.step)
+			continue
+		}
+		require.NotNil(t, reqErr, "step %d", tc.step)
+		assert.Equal(t, http.StatusBadRequest, reqErr.StatusCode)
+		assert.Equal(t, "step_minutes", reqErr.Field)
+		assert.Equal(t, tr(LocaleEn, "slots.invalid_step", slotMinStepMinutes), reqErr.Message)
+	}
+}
+
+func TestSuggestSlotsAlignsStartsFromLocalMidnight(t *testing.T) {
+	p, api := newTestPlugin(t)
+	api.On("GetUser", "organizer").Return(&model.User{Id: "organizer"}, nil)
+	loc := loadLocation(DefaultTimezone)
+	now := time.Date(2025, 1, 20, 10, 7, 0, 0, loc)
+
+	slots, _, reqErr := p.suggestSlots(&SlotSuggestionRequest{
+		DurationMinutes: 30,
+		StepMinutes:     45,
+		To:              "2025-01-20",
+		WorkdayStart:    "00:00",
+		WorkdayEnd:      "23:59",
+		Limit:           slotMaxLimit,
+	}, "organizer", LocaleRu, now)
+
+	require.Nil(t, reqErr)
+	require.NotEmpty(t, slots)
+	starts := map[string]bool{}
+	for _, slot := range slots {
+		starts[slot.StartTimeLocal] = true
+		start, err := time.Parse(time.RFC3339, slot.StartTimeLocal)
+		require.NoError(t, err)
+		assert.Zero(t, (start.Hour()*60+start.Minute())%45, slot.StartTimeLocal)
+	}
+	// 10:30 is the first multiple of 45 minutes since midnight, not 10:45
+	assert.True(t, starts[time.Date(2025, 1, 20, 10, 30, 0, 0, loc).Format(time.RFC3339)])
+}
+
+func TestAlignSlotStart(t *testing.T) {
+	loc := loadLocation(DefaultTimezone)
+
+	assert.Equal(t, time.Date(2025, 1, 20, 10, 30, 0, 0, loc), alignSlotStart(time.Date(2025, 1, 20, 10, 7, 30, 0, loc), 45))
+	assert.Equal(t, time.Date(2025, 1, 20, 10, 30, 0, 0, loc), alignSlotStart(time.Date(2025, 1, 20, 10, 30, 0, 0, loc), 15))
+	assert.Equal(t, time.Date(2025, 1, 21, 0, 0, 0, 0, loc), alignSlotStart(time.Date(2025, 1, 20, 22, 31, 0, 0, loc), 90))
+}
+
+func TestSuggestSlotsReportsTheInvalidWorkdayBound(t *testing.T) {
+	for _, tc := range []struct {
+		start, end, field string
+	}{
+		{"9am", "18:00", "workday_start"},
+		{"09:00", "6pm", "workday_end"},
+		{"18:00", "09:00", "workday_end"},
+		{"09:00", "09:20", "workday_end"},
+	} {
+		p, api := newTestPlugin(t)
+		api.On("GetUser", "organizer").Return(&model.User{Id: "organizer"}, nil)
+		now := time.Date(2025, 1, 20, 10, 7, 0, 0, loadLocation(DefaultTimezone))
+
+		_, _, reqErr := p.suggestSlots(&SlotSuggestionRequest{DurationMinutes: 30, WorkdayStart: tc.start, WorkdayEnd: tc.end}, "organizer", LocaleEn, now)
+
+		require.NotNil(t, reqErr, "%s-%s", tc.start, tc.end)
+		assert.Equal(t, http.StatusBadRequest, reqErr.StatusCode)
+		assert.Equal(t, tc.field, reqErr.Field, "%s-%s", tc.start, tc.end)
+		assert.Equal(t, tr(LocaleEn, "slots.invalid_workday"), reqErr.Message)
+	}
+}
+
+func TestScoreSlot(t *testing.T) {
+	loc := loadLocation(DefaultTimezone)
+	at := func(hour, minute int) time.Time { return time.Date(2025, 1, 21, hour, minute, 0, 0, loc) }
+	users := []*model.User{{Id: "organizer"}}
+	busy := map[string][]BusyInterval{"organizer": {{UserID: "organizer", MeetingID: "busy", Start: at(12, 0), End: at(13, 0)}}}
+
+	for _, tc := range []struct {
+		name  string
+		start time.Time
+		score int
+		ok    bool
+	}{
+		{"free core hours", at(14, 0), 2, true},
+		{"right after the meeting", at(13, 0), 1, true},
+		{"within the buffer after the meeting", at(13, 10), 1, true},
+		{"right before the meeting", at(11, 0), 1, true},
+		{"first hour of the workday", at(9, 0), 1, true},
+		{"overlaps the meeting", at(12, 30), 0, false},
+		{"after the workday", at(17, 30), 0, false},
+	} {
+		score, ok := scoreSlot(users, busy, tc.start, tc.start.Add(time.Hour), 9*60, 18*60, false)
+		assert.Equal(t, tc.ok, ok, tc.name)
+		assert.Equal(t, tc.score, score, tc.name)
+	}
+}
+
+func TestSuggestSlotsAvoidsStoredMeetings(t *testing.T) {
+	p, api := newTestPlugin(t)
+	api.On("GetUser", "organizer").Return(&model.User{Id: "organizer"}, nil)
+	loc := loadLocation(DefaultTimezone)
+	now := time.Date(2025, 1, 20, 18, 0, 0, 0, loc)
+	require.NoError(t, p.saveMeeting(&Meeting{
+		ID:              "busy",
+		OperationType:   OperationScheduledMeeting,
+		Status:          MeetingStatusScheduled,
+		ChannelID:       "channel",
+		OrganizerID:     "organizer",
+		StartAt:         model.GetMillisForTime(time.Date(2025, 1, 21, 12, 0, 0, 0, loc)),
+		DurationMinutes: 60,
+		Timezone:        DefaultTimezone,
+	}))
+
+	slots, _, reqErr := p.suggestSlots(&SlotSuggestionRequest{
+		DurationMinutes: 60,
+		StepMinutes:     60,
+		From:            "2025-01-21",
+		To:              "2025-01-21",
+		Limit:           slotMaxLimit,
+	}, "organizer", LocaleEn, now)
+
+	require.Nil(t, reqErr)
+	require.NotEmpty(t, slots)
+	busyStart, busyEnd := time.Date(2025, 1, 21, 12, 0, 0, 0, loc), time.Date(2025, 1, 21, 13, 0, 0, 0, loc)
+	scores := map[string]int{}
+	for i, slot := range slots {
+		start, err := time.Parse(time.RFC3339, slot.StartTimeUTC)
+		require.NoError(t, err)
+		end, err := time.Parse(time.RFC3339, slot.EndTimeUTC)
+		require.NoError(t, err)
+		assert.False(t, start.Before(busyEnd) && busyStart.Before(end), "%s overlaps the stored meeting", slot.StartTimeLocal)
+		for _, other := range slots[:i] {
+			assert.False(t, overlapsSlots(slot, []*SuggestedSlot{other}), "%s overlaps %s", slot.StartTimeLocal, other.StartTimeLocal)
+		}
+		if i > 0 {
+			assert.LessOrEqual(t, slot.Score, slots[i-1].Score)
+		}
+		scores[start.In(loc).Format("15:04")] = slot.Score
+	}
+
+	// Best slots are away from the meeting and the workday edges; the buffer lowers the ones next to it
+	assert.Equal(t, 2, slots[0].Score)
+	assert.Equal(t, 2, scores["14:00"])
+	assert.Equal(t, 1, scores["13:00"])
+	assert.NotContains(t, scores, "12:00")
+	assert.Equal(t, 1, scores["11:00"])
+}
+
+func TestSuggestSlotsDropsOverlappingSuggestions(t *testing.T) {
+	p, api := newTestPlugin(t)
+	api.On("GetUser", "organizer").Return(&model.User{Id: "organizer"}, nil)
+	loc := loadLocation(DefaultTimezone)
+	require.NoError(t, p.saveMeeting(&Meeting{
+		ID:              "busy",
+		OperationType:   OperationScheduledMeeting,
+		Status:          MeetingStatusScheduled,
+		ChannelID:       "channel",
+		OrganizerID:     "organizer",
+		StartAt:         model.GetMillisForTime(time.Date(2025, 1, 21, 12, 0, 0, 0, loc)),
+		DurationMinutes: 60,
+		Timezone:        DefaultTimezone,
+	}))
+
+	// Starts every 15 minutes give many equally good candidates that overlap each other
+	slots, _, reqErr := p.suggestSlots(&SlotSuggestionRequest{
+		DurationMinutes: 60,
+		StepMinutes:     15,
+		From:            "2025-01-21",
+		To:              "2025-01-21",
+		Limit:           slotMaxLimit,
+	}, "organizer", LocaleEn, time.Date(2025, 1, 20, 18, 0, 0, 0, loc))
+
+	require.Nil(t, reqErr)
+	require.NotEmpty(t, slots)
+	for i, slot := range slots {
+		assert.False(t, overlapsSlots(slot, slots[:i]), "%s overlaps an earlier suggestion", slot.StartTimeLocal)
+		assert.False(t, slot.StartTimeUTC < "2025-01-21T10:00:00Z" && "2025-01-21T09:00:00Z" < slot.EndTimeUTC, "%s overlaps the stored meeting", slot.StartTimeLocal)
+	}
+	assert.Equal(t, 2, slots[0].Score)
+}
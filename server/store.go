@@ -0,0 +1,349 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+)
+
+// KV store key prefixes
+const (
+	kvMeetingPrefix        = "meeting_"
+	kvChannelIndexPrefix   = "meetings_channel_"
+	kvUserIndexPrefix      = "meetings_user_"
+	kvUpcomingIndexKey     = "meetings_upcoming"
+	kvPendingIndexKey      = "meetings_pending"
+	kvSeriesPrefix         = "series_"
+	kvActiveSeriesIndexKey = "recurring_active"
+	kvIndexUpdateAttempts  = 5
+)
+
+// ErrMeetingNotFound is returned when a meeting is missing from the KV store
+var ErrMeetingNotFound = errors.New("meeting not found")
+
+// ErrSeriesNotFound is returned when a recurring series is missing from the KV store
+var ErrSeriesNotFound = errors.New("series not found")
+
+// saveMeeting stores the meeting and registers it in the user indexes and, while it is scheduled, in the channel and upcoming indexes
+func (p *Plugin) saveMeeting(meeting *Meeting) error {
+	if err := p.updateMeeting(meeting); err != nil {
+		return err
+	}
+
+	for _, userID := range meeting.UserIDs() {
+		if err := p.addToIndex(kvUserIndexPrefix+userID, meeting.ID); err != nil {
+			return err
+		}
+	}
+
+	// Scheduled meetings are tracked by background jobs until they are over, which also unindexes them from the channel
+	if meeting.OperationType != OperationInstantCall {
+		if err := p.addToIndex(kvChannelIndexPrefix+meeting.ChannelID, meeting.ID); err != nil {
+			return err
+		}
+		if err := p.addToIndex(kvUpcomingIndexKey, meeting.ID); err != nil {
+			return err
+		}
+	}
+
+	// Pending meetings wait for the provider callback and expire without it
+	if meeting.IsPending() {
+		if err := p.addToIndex(kvPendingIndexKey, meeting.ID); err != nil {
+			return err
+		}
+	}
+
+	p.API.LogDebug("[Kontur] Meeting saved", "meeting_id", meeting.ID)
+	return nil
+}
+
+// updateMeeting overwrites the stored meeting record without touching the indexes
+func (p *Plugin) updateMeeting(meeting *Meeting) error {
+	meeting.UpdateAt = model.GetMillis()
+
+	data, err := json.Marshal(meeting)
+	if err != nil {
+		return fmt.Errorf("failed to marshal meeting: %w", err)
+	}
+
+	if appErr := p.API.KVSet(kvMeetingPrefix+meeting.ID, data); appErr != nil {
+		return fmt.Errorf("failed to save meeting %s: %s", meeting.ID, appErr.Error())
+	}
+	return nil
+}
+
+// modifyMeeting applies a change to the stored meeting using compare-and-set, so concurrent
+// updates (e.g. a burst of provider events) don't overwrite each other
+func (p *Plugin) modifyMeeting(meetingID string, change func(meeting *Meeting) bool) (*Meeting, error) {
+	for attempt := 0; attempt < kvIndexUpdateAttempts; attempt++ {
+		oldData, appErr := p.API.KVGet(kvMeetingPrefix + meetingID)
+		if appErr != nil {
+			return nil, fmt.Errorf("failed to load meeting %s: %s", meetingID, appErr.Error())
+		}
+		if oldData == nil {
+			return nil, ErrMeetingNotFound
+		}
+
+		var meeting Meeting
+		if err := json.Unmarshal(oldData, &meeting); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal meeting %s: %w", meetingID, err)
+		}
+		if !change(&meeting) {
+			return &meeting, nil
+		}
+
+		meeting.UpdateAt = model.GetMillis()
+		newData, err := json.Marshal(&meeting)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal meeting: %w", err)
+		}
+
+		ok, appErr := p.API.KVSetWithOptions(kvMeetingPrefix+meetingID, newData, model.PluginKVSetOptions{
+			Atomic:   true,
+			OldValue: oldData,
+		})
+		if appErr != nil {
+			return nil, fmt.Errorf("failed to save meeting %s: %s", meetingID, appErr.Error())
+		}
+		if ok {
+			return &meeting, nil
+		}
+	}
+
+	return nil, fmt.Errorf("failed to save meeting %s: too many concurrent updates", meetingID)
+}
+
+// getMeeting loads a meeting by ID
+func (p *Plugin) getMeeting(meetingID string) (*Meeting, error) {
+	data, appErr := p.API.KVGet(kvMeetingPrefix + meetingID)
+	if appErr != nil {
+		return nil, fmt.Errorf("failed to load meeting %s: %s", meetingID, appErr.Error())
+	}
+	if data == nil {
+		return nil, ErrMeetingNotFound
+	}
+
+	var meeting Meeting
+	if err := json.Unmarshal(data, &meeting); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal meeting %s: %w", meetingID, err)
+	}
+	return &meeting, nil
+}
+
+// getChannelMeetings returns the scheduled meetings of a channel that the reminder job hasn't pruned yet,
+// so cancelled and finished ones may still be among them
+func (p *Plugin) getChannelMeetings(channelID string) ([]*Meeting, error) {
+	return p.getIndexedMeetings(kvChannelIndexPrefix + channelID)
+}
+
+// getUserMeetings returns all stored meetings the user organizes or participates in, finished and cancelled ones included
+func (p *Plugin) getUserMeetings(userID string) ([]*Meeting, error) {
+	return p.getIndexedMeetings(kvUserIndexPrefix + userID)
+}
+
+// getUpcomingMeetings returns meetings that haven't been pruned from the upcoming index yet
+func (p *Plugin) getUpcomingMeetings() ([]*Meeting, error) {
+	return p.getIndexedMeetings(kvUpcomingIndexKey)
+}
+
+// getPendingMeetings returns meetings waiting for the provider callback
+func (p *Plugin) getPendingMeetings() ([]*Meeting, error) {
+	return p.getIndexedMeetings(kvPendingIndexKey)
+}
+
+// getIndexedMeetings loads all meetings referenced by an index key
+func (p *Plugin) getIndexedMeetings(indexKey string) ([]*Meeting, error) {
+	ids, _, err := p.getIndex(indexKey)
+	if err != nil {
+		return nil, err
+	}
+
+	meetings := make([]*Meeting, 0, len(ids))
+	for _, id := range ids {
+		meeting, err := p.getMeeting(id)
+		if err != nil {
+			// Index entries may outlive their meeting, skip them
+			p.API.LogWarn("[Kontur] Failed to load indexed meeting", "index", indexKey, "meeting_id", id, "error", err.Error())
+			continue
+		}
+		meetings = append(meetings, meeting)
+	}
+	return meetings, nil
+}
+
+// getIndex returns the meeting IDs stored under an index key along with the raw value
+func (p *Plugin) getIndex(indexKey string) ([]string, []byte, error) {
+	data, appErr := p.API.KVGet(indexKey)
+	if appErr != nil {
+		return nil, nil, fmt.Errorf("failed to load index %s: %s", indexKey, appErr.Error())
+	}
+
+	ids := []string{}
+	if data != nil {
+		if err := json.Unmarshal(data, &ids); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal index %s: %w", indexKey, err)
+		}
+	}
+	return ids, data, nil
+}
+
+// addToIndex appends a meeting ID to an index
+func (p *Plugin) addToIndex(indexKey, meetingID string) error {
+	return p.modifyIndex(indexKey, func(ids []string) ([]string, bool) {
+		for _, id := range ids {
+			if id == meetingID {
+				return ids, false
+			}
+		}
+		return append(ids, meetingID), true
+	})
+}
+
+// removeFromIndex removes meeting IDs from an index
+func (p *Plugin) removeFromIndex(indexKey string, meetingIDs ...string) error {
+	remove := make(map[string]bool, len(meetingIDs))
+	for _, id := range meetingIDs {
+		remove[id] = true
+	}
+
+	return p.modifyIndex(indexKey, func(ids []string) ([]string, bool) {
+		kept := make([]string, 0, len(ids))
+		for _, id := range ids {
+			if !remove[id] {
+				kept = append(kept, id)
+			}
+		}
+		return kept, len(kept) != len(ids)
+	})
+}
+
+// modifyIndex applies a change to an index using compare-and-set, so concurrent
+// writers on other cluster nodes don't lose each other's updates
+func (p *Plugin) modifyIndex(indexKey string, change func(ids []string) ([]string, bool)) error {
+	for attempt := 0; attempt < kvIndexUpdateAttempts; attempt++ {
+		ids, oldData, err := p.getIndex(indexKey)
+		if err != nil {
+			return err
+		}
+
+		newIDs, changed := change(ids)
+		if !changed {
+			return nil
+		}
+
+		newData, err := json.Marshal(newIDs)
+		if err != nil {
+			return fmt.Errorf("failed to marshal index %s: %w", indexKey, err)
+		}
+
+		ok, appErr := p.API.KVSetWithOptions(indexKey, newData, model.PluginKVSetOptions{
+			Atomic:   true,
+			OldValue: oldData,
+		})
+		if appErr != nil {
+			return fmt.Errorf("failed to update index %s: %s", indexKey, appErr.Error())
+		}
+		if ok {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("failed to update index %s: too many concurrent updates", indexKey)
+}
+
+// saveSeries stores a new series and registers it for occurrence generation
+func (p *Plugin) saveSeries(series *Series) error {
+	series.UpdateAt = model.GetMillis()
+
+	data, err := json.Marshal(series)
+	if err != nil {
+		return fmt.Errorf("failed to marshal series: %w", err)
+	}
+	if appErr := p.API.KVSet(kvSeriesPrefix+series.ID, data); appErr != nil {
+		return fmt.Errorf("failed to save series %s: %s", series.ID, appErr.Error())
+	}
+
+	if series.Status == SeriesStatusActive {
+		return p.addToIndex(kvActiveSeriesIndexKey, series.ID)
+	}
+	return nil
+}
+
+// getSeries loads a series by ID
+func (p *Plugin) getSeries(seriesID string) (*Series, error) {
+	data, appErr := p.API.KVGet(kvSeriesPrefix + seriesID)
+	if appErr != nil {
+		return nil, fmt.Errorf("failed to load series %s: %s", seriesID, appErr.Error())
+	}
+	if data == nil {
+		return nil, ErrSeriesNotFound
+	}
+
+	var series Series
+	if err := json.Unmarshal(data, &series); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal series %s: %w", seriesID, err)
+	}
+	return &series, nil
+}
+
+// modifySeries applies a change to the stored series using compare-and-set, so the background job
+// doesn't overwrite a concurrent cancellation
+func (p *Plugin) modifySeries(seriesID string, change func(series *Series) bool) (*Series, error) {
+	for attempt := 0; attempt < kvIndexUpdateAttempts; attempt++ {
+		oldData, appErr := p.API.KVGet(kvSeriesPrefix + seriesID)
+		if appErr != nil {
+			return nil, fmt.Errorf("failed to load series %s: %s", seriesID, appErr.Error())
+		}
+		if oldData == nil {
+			return nil, ErrSeriesNotFound
+		}
+
+		var series Series
+		if err := json.Unmarshal(oldData, &series); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal series %s: %w", seriesID, err)
+		}
+		if !change(&series) {
+			return &series, nil
+		}
+
+		series.UpdateAt = model.GetMillis()
+		newData, err := json.Marshal(&series)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal series: %w", err)
+		}
+
+		ok, appErr := p.API.KVSetWithOptions(kvSeriesPrefix+seriesID, newData, model.PluginKVSetOptions{
+			Atomic:   true,
+			OldValue: oldData,
+		})
+		if appErr != nil {
+			return nil, fmt.Errorf("failed to save series %s: %s", seriesID, appErr.Error())
+		}
+		if ok {
+			return &series, nil
+		}
+	}
+
+	return nil, fmt.Errorf("failed to save series %s: too many concurrent updates", seriesID)
+}
+
+// getActiveSeries returns the series that still generate occurrences
+func (p *Plugin) getActiveSeries() ([]*Series, error) {
+	ids, _, err := p.getIndex(kvActiveSeriesIndexKey)
+	if err != nil {
+		return nil, err
+	}
+
+	seriesList := make([]*Series, 0, len(ids))
+	for _, id := range ids {
+		series, err := p.getSeries(id)
+		if err != nil {
+			p.API.LogWarn("[Kontur] Failed to load indexed series", "series_id", id, "error", err.Error())
+			continue
+		}
+		seriesList = append(seriesList, series)
+	}
+	return seriesList, nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"strings"
+	"time"
+)
+
+// DefaultReferenceTimezones are shown next to the meeting time when the setting is empty
+const DefaultReferenceTimezones = "Europe/Moscow"
+
+// formatMeetingTime formats the time in the meeting zone followed by the reference zones,
+// e.g. "20.01.2025, 15:00 (UTC+07) · 11:00 МСК · 09:00 CET"
+func (p *Plugin) formatMeetingTime(t time.Time, loc *time.Location, locale string) string {
+	local := t.In(loc)
+	label := zoneLabel(t, loc, locale)
+	if label == tr(locale, "datetime.msk") {
+		label = tr(locale, "datetime.moscow")
+	}
+	text := local.Format(tr(locale, "datetime.layout")) + " (" + label + ")"
+
+	_, offset := local.Zone()
+	for _, ref := range p.referenceLocations() {
+		refTime := t.In(ref)
+		// Zones that show the same clock time add nothing
+		if _, refOffset := refTime.Zone(); refOffset == offset {
+			continue
+		}
+		layout := tr(locale, "datetime.layout_clock")
+		if refTime.Day() != local.Day() {
+			layout = tr(locale, "datetime.layout_short")
+		}
+		text += " · " + refTime.Format(layout) + " " + zoneLabel(t, ref, locale)
+	}
+	return text
+}
+
+// referenceLocations returns the zones from the ReferenceTimezones setting, skipping unknown ones
+func (p *Plugin) referenceLocations() []*time.Location {
+	setting := p.getConfiguration().ReferenceTimezones
+	if strings.TrimSpace(setting) == "" {
+		setting = DefaultReferenceTimezones
+	}
+
+	locations := []*time.Location{}
+	for _, name := range strings.Split(setting, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			p.API.LogWarn("[Kontur] Unknown reference timezone", "timezone", name)
+			continue
+		}
+		locations = append(locations, loc)
+	}
+	return locations
+}
+
+// zoneLabel returns a short zone name at the given moment: "МСК", "CET", "UTC+07"
+func zoneLabel(t time.Time, loc *time.Location, locale string) string {
+	abbreviation, _ := t.In(loc).Zone()
+	switch {
+	case abbreviation == "MSK":
+		return tr(locale, "datetime.msk")
+	case strings.HasPrefix(abbreviation, "+") || strings.HasPrefix(abbreviation, "-"):
+		return "UTC" + abbreviation
+	default:
+		return abbreviation
+	}
+}
@@ -506,16 +506,30 @@ const ScheduleMeetingModal = ({channel, postId, rootId, onClose, onSuccess}) =>
 
       logger.debug('Отправка запроса на создание встречи:', requestBody);
 
-      const response = await fetch('/plugins/com.skyeng.kontur-meeting/api/schedule-meeting', {
+      const sendScheduleRequest = (body) => fetch('/plugins/com.skyeng.kontur-meeting/api/schedule-meeting', {
         method: 'POST',
         credentials: 'same-origin',
         headers: {
           'Content-Type': 'application/json',
           'X-Requested-With': 'XMLHttpRequest'
         },
-        body: JSON.stringify(requestBody)
+        body: JSON.stringify(body)
       });
 
+      let response = await sendScheduleRequest(requestBody);
+
+      // Участники заняты в это время: предлагаем создать встречу всё равно
+      if (response.status === 409) {
+        const conflictResult = await response.clone().json().catch(() => null);
+        if (conflictResult && Array.isArray(conflictResult.conflicts) && conflictResult.conflicts.length > 0) {
+          const conflictMessage = conflictResult.errors && conflictResult.errors[0] ?
+            conflictResult.errors[0].message : 'В это время у участников есть другие встречи.';
+          if (window.confirm(`${conflictMessage}\n\nСоздать встречу всё равно?`)) {
+            response = await sendScheduleRequest({...requestBody, force: true});
+          }
+        }
+      }
+
       if (!response.ok) {
         console.error('[Kontur] Ошибка от сервера:', {
           status: response.status,
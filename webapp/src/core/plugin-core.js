@@ -47,9 +47,7 @@ export class PluginCore {
       this.config = await response.json();
       
       // Map snake_case keys from server to camelCase for compatibility
-      if (this.config.webhook_url) {
-        this.config.WebhookURL = this.config.webhook_url;
-      }
+      this.config.WebhookConfigured = !!this.config.webhook_configured;
       if (this.config.open_in_new_tab !== undefined) {
         this.config.OpenInNewTab = this.config.open_in_new_tab;
       }
@@ -65,7 +63,7 @@ export class PluginCore {
     } catch (error) {
       logger.error('Ошибка загрузки конфигурации', error);
       this.config = { 
-        WebhookURL: '',
+        WebhookConfigured: false,
         OpenInNewTab: true,
         ServiceName: ''
       };
@@ -201,20 +199,12 @@ export class PluginCore {
     return this.config?.ServiceName || '';
   }
 
-  /**
-   * Get webhook URL from configuration
-   * @returns {string} Webhook URL or empty string
-   */
-  getWebhookURL() {
-    return this.config?.WebhookURL || '';
-  }
-
   /**
    * Check if webhook URL is configured
    * @returns {boolean} True if webhook URL is set
    */
   isWebhookConfigured() {
-    return !!(this.config && this.config.WebhookURL);
+    return !!(this.config && this.config.WebhookConfigured);
   }
 
   /**
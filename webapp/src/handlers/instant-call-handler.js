@@ -6,36 +6,6 @@
 import { logger } from '../utils/logger.js';
 import { formatErrorMessage } from '../utils/helpers.js';
 
-/**
- * Convert UTC time to Moscow timezone (MSK) in RFC3339 format
- * @param {Date} date - Date object (defaults to current time)
- * @returns {string} Time in MSK timezone (RFC3339 format)
- */
-function convertToMSK(date = new Date()) {
-  // Get time components in Moscow timezone
-  const formatter = new Intl.DateTimeFormat('en-US', {
-    timeZone: 'Europe/Moscow',
-    year: 'numeric',
-    month: '2-digit',
-    day: '2-digit',
-    hour: '2-digit',
-    minute: '2-digit',
-    second: '2-digit',
-    hour12: false
-  });
-
-  const parts = formatter.formatToParts(date);
-  const year = parts.find(p => p.type === 'year').value;
-  const month = parts.find(p => p.type === 'month').value;
-  const day = parts.find(p => p.type === 'day').value;
-  const hours = parts.find(p => p.type === 'hour').value;
-  const minutes = parts.find(p => p.type === 'minute').value;
-  const seconds = parts.find(p => p.type === 'second').value;
-
-  // Format as RFC3339 with +03:00 offset (MSK is always UTC+3)
-  return `${year}-${month}-${day}T${hours}:${minutes}:${seconds}+03:00`;
-}
-
 /**
  * Handle instant call creation
  * @param {Object} channel - Current channel object
@@ -65,8 +35,6 @@ export async function handleInstantCall(channel, pluginCore, context = {}) {
       return;
     }
 
-    const webhookURL = pluginCore.getWebhookURL();
-
     // Get current user info from Redux store
     const currentUser = pluginCore.getUser();
 
@@ -76,112 +44,68 @@ export async function handleInstantCall(channel, pluginCore, context = {}) {
       return;
     }
 
-    logger.debug('Текущий пользователь:', {
-      id: currentUser.id,
-      username: currentUser.username,
-      email: currentUser.email || '(не указан)'
-    });
-
-    // Get current time
-    const now = new Date();
-
-    // Prepare webhook payload
-    const webhookPayload = {
-      operation_type: 'instant_call',  // Тип операции: быстрый созвон
+    // Prepare request payload (webhook payload is built on the server)
+    const requestPayload = {
       channel_id: channel.id,
-      channel_name: channel.display_name || channel.name,
-      channel_type: channel.type,
+      team_id: channel.team_id || '',
       user_id: currentUser.id,
-      username: currentUser.username,
-      user_email: currentUser.email || null,  // Email может быть не заполнен
-      start_time_utc: now.toISOString(),  // UTC time in RFC3339 format
-      start_time_msk: convertToMSK(now),   // MSK time in RFC3339 format
-      timestamp: now.toISOString(),
-      root_id: rootId || '',  // ID родительского поста (root сообщения треда)
-      is_thread_reply: !!rootId  // Флаг что встреча создана в треде
+      root_id: rootId || ''  // ID родительского поста (root сообщения треда)
     };
 
     logger.debug('Создание быстрого созвона (instant_call)');
-    logger.debug('Отправка запроса к вебхуку:', webhookURL);
-    logger.debug('Payload:', JSON.stringify(webhookPayload, null, 2));
+    logger.debug('Payload:', JSON.stringify(requestPayload, null, 2));
 
-    // Send request to webhook to create meeting
-    const webhookResponse = await fetch(webhookURL, {
+    // Server creates the meeting via webhook and publishes the post
+    const response = await fetch('/plugins/com.skyeng.kontur-meeting/api/instant-call', {
       method: 'POST',
+      credentials: 'same-origin',
       headers: {
-        'Content-Type': 'application/json'
+        'Content-Type': 'application/json',
+        'X-Requested-With': 'XMLHttpRequest'
       },
-      body: JSON.stringify(webhookPayload)
+      body: JSON.stringify(requestPayload)
     });
 
-    if (!webhookResponse.ok) {
-      // Try to parse error response from webhook
-      let errorMessage = `Вебхук вернул ошибку: ${webhookResponse.status} ${webhookResponse.statusText}`;
-
-      try {
-        const responseText = await webhookResponse.text();
-        if (responseText) {
-          const errorData = JSON.parse(responseText);
-
-          // Check for structured n8n error response
-          if (errorData.status === 'error' && errorData.message) {
-            errorMessage = errorData.message;
-          } else if (errorData.message) {
-            errorMessage = errorData.message;
-          } else if (errorData.error) {
-            errorMessage = errorData.error;
-          }
-        }
-      } catch (parseError) {
-        // If parsing fails, use default error message
-        logger.warn('[Meeting] Не удалось распарсить ошибку от вебхука', parseError);
-      }
-
-      throw new Error(errorMessage);
-    }
-
     // Safely parse response - handle empty body
-    let webhookData = null;
-    const responseText = await webhookResponse.text();
+    let result = null;
+    const responseText = await response.text();
 
     if (responseText) {
       try {
-        webhookData = JSON.parse(responseText);
-        logger.debug('Ответ от вебхука:', webhookData);
+        result = JSON.parse(responseText);
       } catch (e) {
-        logger.error('[Meeting] Не удалось распарсить JSON ответа вебхука', {
+        logger.error('[Meeting] Не удалось распарсить JSON ответа сервера', {
           error: e.message,
           responseText: responseText.substring(0, 200) // Log first 200 chars
         });
-        alert('❌ Вебхук вернул некорректный ответ. Обратитесь в ~ai-automation-center.');
-        return;
       }
-    } else {
-      // Empty response - this is an error
-      logger.error('[Meeting] Пустой ответ от вебхука при создании быстрого созвона');
-      alert('❌ Вебхук не вернул данные для встречи. Обратитесь в ~ai-automation-center.');
-      return;
     }
 
-    // Check if meeting_url or room_url is present in response
-    const roomUrl = webhookData?.meeting_url || webhookData?.room_url;
+    if (!response.ok) {
+      let errorMessage = `Сервер вернул ошибку: ${response.status} ${response.statusText}`;
+      if (result && Array.isArray(result.errors) && result.errors.length > 0) {
+        errorMessage = result.errors.map(err => err.message).join('\n');
+      } else if (result && result.message) {
+        errorMessage = result.message;
+      }
+      throw new Error(errorMessage);
+    }
+
+    const roomUrl = result?.room_url;
+
+    // Async provider mode: the room link is posted to the channel once it's ready
+    if (!roomUrl && result?.pending) {
+      logger.debug('Встреча создаётся асинхронно:', result.meeting_id);
+      return;
+    }
 
     if (!roomUrl) {
-      // Если нет URL, но есть success: true, просто показываем сообщение
-      if (webhookData?.success) {
-        const serviceName = pluginCore.getServiceName();
-        const serviceText = serviceName ? ` ${serviceName}` : ' видеосвязи';
-        alert(`✅ Комната${serviceText} создана!`);
-        return;
-      }
-      logger.warn('Неожиданный ответ от вебхука:', webhookData);
+      logger.warn('Неожиданный ответ от сервера:', result);
       alert('❌ Вебхук не вернул ссылку на комнату. Обратитесь в ~ai-automation-center.');
       return;
     }
 
-    // Create post in the channel or thread
-    const postMessage = `📞 Я создал встречу: ${roomUrl}`;
-    await pluginCore.createPost(channel.id, postMessage, rootId || null);
+    logger.debug('Встреча создана:', roomUrl);
 
     // Open meeting room in new tab (default: true)
     const openInNewTab = pluginCore.shouldOpenInNewTab();
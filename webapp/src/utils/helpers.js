@@ -5,8 +5,10 @@
  */
 export const formatWebhookError = (config) => {
   let errorMessage = '❌ Не удалось создать встречу.\n\n';
-  errorMessage += '🔌 Не удалось подключиться к вебхуку:\n';
-  errorMessage += (config && config.WebhookURL) || 'URL не настроен';
+  errorMessage += '🔌 Не удалось подключиться к серверу плагина';
+  if (config && !config.WebhookConfigured) {
+    errorMessage += ' (URL вебхука не настроен)';
+  }
   errorMessage += '\n\nПроверьте:\n';
   errorMessage += '1. n8n запущен и доступен\n';
   errorMessage += '2. Workflow активирован\n';